@@ -2,41 +2,225 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/infrastructure/calendar"
+	"pullrequest-manager/internal/infrastructure/codehost/github"
+	"pullrequest-manager/internal/infrastructure/database/memory"
 	"pullrequest-manager/internal/infrastructure/database/pg"
+	"pullrequest-manager/internal/infrastructure/handlers"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"pullrequest-manager/internal/infrastructure/webhooks"
+	"syscall"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	shutdownTimeout             = 10 * time.Second
+	idempotencyCleanupInterval  = time.Hour
+	defaultCalendarSyncInterval = time.Hour
+)
+
+const (
+	storagePostgres = "postgres"
+	storageMemory   = "memory"
+)
+
+type storage struct {
+	pool         *pgxpool.Pool
+	userRepo     repositories.User
+	prRepo       repositories.PullRequest
+	teamRepo     repositories.Team
+	statusRepo   repositories.Status
+	settingsRepo repositories.TeamSettings
+	webhookRepo  repositories.WebhookSubscription
+	deadLetters  repositories.WebhookDeadLetter
+	outboxRepo   repositories.Outbox
+	identityRepo repositories.UserIdentity
+	txManager    repositories.TxManager
+	idempotency  repositories.IdempotencyKey
+	unavailable  repositories.Unavailability
+	calendars    repositories.CalendarSource
+}
+
 func main() {
-	connString := os.Getenv("DATABASE_URL")
-	if connString == "" {
-		log.Fatal("DATABASE_URL environment variable not set")
-	}
+	storageKind := flag.String("storage", storagePostgres, "storage backend: postgres or memory")
+	flag.Parse()
 
 	port := os.Getenv("SERVER_PORT")
 	if port == "" {
 		port = "8080"
-	} else {
-		port = ":" + port
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var st *storage
+	switch *storageKind {
+	case storagePostgres:
+		connString := os.Getenv("DATABASE_URL")
+		if connString == "" {
+			log.Fatal("DATABASE_URL environment variable not set")
+		}
+
+		pool, err := pgxpool.New(ctx, connString)
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer pool.Close()
 
-	pool, err := pgxpool.New(ctx, connString)
+		st = newPostgresStorage(pool)
+	case storageMemory:
+		st = newMemoryStorage()
+	default:
+		log.Fatalf("Unknown storage %q, expected %q or %q", *storageKind, storagePostgres, storageMemory)
+	}
+
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		if st.pool == nil {
+			log.Fatalf("Migrations require %q storage", storagePostgres)
+		}
+		if err := runMigrate(ctx, st.pool, args[1:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	selector, err := newReviewerSelectorFromEnv(st.prRepo, st.settingsRepo)
 	if err != nil {
-		log.Fatalf("Failed to connect to database:", err)
+		log.Fatalf("Failed to configure reviewer selection: %v", err)
 	}
-	defer pool.Close()
 
-	prRepo := pg.NewPullRequestRepository(pool)
-	statusRepo := pg.NewStatusRepository(pool)
-	teamRepo := pg.NewTeamRepository(pool)
-	userRepo := pg.NewUserRepository(pool)
+	dispatcher := webhooks.NewDispatcher(st.webhookRepo, st.deadLetters, webhooks.DefaultOptions())
 
-	teamService, err := services.NewDefaultPullRequestService(userRepo, prRepo, teamRepo, statusRepo)
-	_ = teamService
+	publishers := []services.EventPublisher{dispatcher}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		client := github.NewClient(os.Getenv("GITHUB_API_URL"), token, nil)
+		reviewerSync := services.NewReviewerSync(client, st.userRepo, st.identityRepo, services.DefaultReviewerSyncOptions())
+		publishers = append(publishers, reviewerSync)
+	}
+
+	relay := services.NewOutboxRelay(st.outboxRepo, publishers, services.DefaultOutboxRelayOptions())
+	go relay.Run(ctx)
 
+	prService, err := services.NewDefaultPullRequestService(
+		st.userRepo,
+		st.prRepo,
+		st.teamRepo,
+		st.statusRepo,
+		st.settingsRepo,
+		selector,
+		st.txManager,
+		st.unavailable,
+	)
+	if err != nil {
+		log.Fatalf("Failed to create pull request service: %v", err)
+	}
+
+	idempotencyTTL := services.DefaultIdempotencyTTL
+	if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+		idempotencyTTL, err = time.ParseDuration(v)
+		if err != nil || idempotencyTTL <= 0 {
+			log.Fatalf("Invalid IDEMPOTENCY_TTL %q: expected a positive duration such as 24h", v)
+		}
+	}
+	idempotency := services.NewDefaultIdempotencyService(st.idempotency, idempotencyTTL)
+	go idempotency.RunCleanup(ctx, idempotencyCleanupInterval)
+
+	calendarSyncInterval := defaultCalendarSyncInterval
+	if v := os.Getenv("CALENDAR_SYNC_INTERVAL"); v != "" {
+		calendarSyncInterval, err = time.ParseDuration(v)
+		if err != nil || calendarSyncInterval <= 0 {
+			log.Fatalf("Invalid CALENDAR_SYNC_INTERVAL %q: expected a positive duration such as 1h", v)
+		}
+	}
+	availability := services.NewDefaultAvailabilityService(
+		st.userRepo,
+		st.unavailable,
+		st.calendars,
+		st.txManager,
+		calendar.NewHTTPFetcher(nil),
+	)
+	go availability.RunCalendarSync(ctx, calendarSyncInterval)
+
+	router := handlers.NewRouter(
+		handlers.NewHandler(prService),
+		handlers.NewHealthHandler(st.pool, st.statusRepo),
+		handlers.NewWebhookHandler(services.NewDefaultWebhookService(st.webhookRepo)),
+		handlers.NewCodeHostHandler(
+			services.NewDefaultCodeHostService(prService, st.userRepo, st.identityRepo),
+			handlers.CodeHostSecrets{
+				GitHub: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+				GitLab: os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+			},
+		),
+		handlers.NewAvailabilityHandler(availability),
+		idempotency,
+	)
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down server gracefully: %v", err)
+		}
+	}()
+
+	log.Printf("Listening on %s with %s storage", server.Addr, *storageKind)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
+
+func newPostgresStorage(pool *pgxpool.Pool) *storage {
+	return &storage{
+		pool:         pool,
+		userRepo:     pg.NewUserRepository(pool),
+		prRepo:       pg.NewPullRequestRepository(pool),
+		teamRepo:     pg.NewTeamRepository(pool),
+		statusRepo:   pg.NewStatusRepository(pool),
+		settingsRepo: pg.NewTeamSettingsRepository(pool),
+		webhookRepo:  pg.NewWebhookSubscriptionRepository(pool),
+		deadLetters:  pg.NewWebhookDeadLetterRepository(pool),
+		outboxRepo:   pg.NewOutboxRepository(pool),
+		identityRepo: pg.NewUserIdentityRepository(pool),
+		txManager:    pg.NewTxManager(pool),
+		idempotency:  pg.NewIdempotencyRepository(pool),
+		unavailable:  pg.NewUnavailabilityRepository(pool),
+		calendars:    pg.NewCalendarSourceRepository(pool),
+	}
+}
+
+func newMemoryStorage() *storage {
+	statusRepo := memory.NewStatusRepository()
+	outboxRepo := memory.NewOutboxRepository()
+	return &storage{
+		userRepo:     memory.NewUserRepository(),
+		prRepo:       memory.NewPullRequestRepository(statusRepo, outboxRepo),
+		teamRepo:     memory.NewTeamRepository(),
+		statusRepo:   statusRepo,
+		settingsRepo: memory.NewTeamSettingsRepository(),
+		webhookRepo:  memory.NewWebhookSubscriptionRepository(),
+		deadLetters:  memory.NewWebhookDeadLetterRepository(),
+		outboxRepo:   outboxRepo,
+		identityRepo: memory.NewUserIdentityRepository(),
+		txManager:    memory.NewTxManager(),
+		idempotency:  memory.NewIdempotencyRepository(),
+		unavailable:  memory.NewUnavailabilityRepository(),
+		calendars:    memory.NewCalendarSourceRepository(),
+	}
 }
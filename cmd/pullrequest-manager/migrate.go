@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"pullrequest-manager/database/migrations"
+	"pullrequest-manager/internal/infrastructure/database/migrator"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "usage: pullrequest-manager migrate up | down [N] | status | goto N | force N"
+
+var errMigrateUsage = errors.New(migrateUsage)
+
+func runMigrate(ctx context.Context, pool *pgxpool.Pool, args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	m, err := migrator.New(pool, migrations.PG, "pg")
+	if err != nil {
+		return fmt.Errorf("load migrations: %w", err)
+	}
+
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return errMigrateUsage
+			}
+		}
+		return m.Down(ctx, steps)
+	case "goto", "force":
+		if len(args) < 2 {
+			return errMigrateUsage
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return errMigrateUsage
+		}
+		if args[0] == "force" {
+			return m.Force(ctx, version)
+		}
+		return m.Goto(ctx, version)
+	case "status":
+		st, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(st)
+		return nil
+	default:
+		return errMigrateUsage
+	}
+}
+
+func printMigrationStatus(st *migrator.Status) {
+	fmt.Printf("current version: %d", st.Version)
+	if st.Dirty {
+		fmt.Print(" (dirty)")
+	}
+	fmt.Println()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATE")
+	for _, mig := range st.Migrations {
+		state := "pending"
+		if mig.Version <= st.Version {
+			state = "applied"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", mig.Version, mig.Name, state)
+	}
+	tw.Flush()
+}
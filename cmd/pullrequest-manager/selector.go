@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// newReviewerSelectorFromEnv reads the deployment-wide strategy from
+// REVIEWER_STRATEGY, per-team overrides from REVIEWER_STRATEGY_TEAMS
+// ("payments=round_robin,security=weighted_random") and weighted_random
+// weights from REVIEWER_WEIGHTS ("<user uuid>=2,<user uuid>=0.5"). A strategy
+// stored in team settings takes precedence over both.
+func newReviewerSelectorFromEnv(
+	loadCounter services.ReviewLoadCounter,
+	settingsRepo repositories.TeamSettings,
+) (services.ReviewerSelector, error) {
+	weights, err := parseReviewerWeights(os.Getenv("REVIEWER_WEIGHTS"))
+	if err != nil {
+		return nil, err
+	}
+	opts := services.SelectorOptions{Weights: weights, LoadCounter: loadCounter}
+
+	selectors := make(map[string]services.ReviewerSelector)
+	selectorFor := func(strategy string) (services.ReviewerSelector, error) {
+		if sel, ok := selectors[strategy]; ok {
+			return sel, nil
+		}
+		sel, err := services.NewReviewerSelector(strategy, opts)
+		if err != nil {
+			return nil, err
+		}
+		selectors[strategy] = sel
+		return sel, nil
+	}
+
+	strategy := os.Getenv("REVIEWER_STRATEGY")
+	if strategy == "" {
+		strategy = services.StrategyRandom
+	}
+	fallback, err := selectorFor(strategy)
+	if err != nil {
+		return nil, fmt.Errorf("REVIEWER_STRATEGY: %w", err)
+	}
+
+	for _, name := range services.Strategies {
+		if _, err := selectorFor(name); err != nil {
+			return nil, err
+		}
+	}
+
+	byTeam := make(map[string]services.ReviewerSelector)
+	for team, teamStrategy := range parsePairs(os.Getenv("REVIEWER_STRATEGY_TEAMS")) {
+		sel, err := selectorFor(teamStrategy)
+		if err != nil {
+			return nil, fmt.Errorf("REVIEWER_STRATEGY_TEAMS for team %q: %w", team, err)
+		}
+		byTeam[team] = sel
+	}
+
+	return services.NewTeamSelector(fallback, selectors, byTeam, settingsRepo), nil
+}
+
+func parseReviewerWeights(raw string) (map[uuid.UUID]float64, error) {
+	weights := make(map[uuid.UUID]float64)
+	for key, value := range parsePairs(raw) {
+		id, err := uuid.Parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("REVIEWER_WEIGHTS: invalid user id %q: %w", key, err)
+		}
+		w, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("REVIEWER_WEIGHTS: invalid weight for %s: %w", id, err)
+		}
+		weights[id] = w
+	}
+	return weights, nil
+}
+
+func parsePairs(raw string) map[string]string {
+	pairs := make(map[string]string)
+	for _, item := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			continue
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return pairs
+}
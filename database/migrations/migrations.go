@@ -0,0 +1,6 @@
+package migrations
+
+import "embed"
+
+//go:embed pg/*.sql
+var PG embed.FS
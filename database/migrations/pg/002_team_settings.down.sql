@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_team_settings_updated_at ON team_settings;
+
+DROP TABLE IF EXISTS team_settings;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS team_settings
+(
+    team_id            UUID PRIMARY KEY,
+    reviewer_count     INTEGER     NOT NULL     DEFAULT 2,
+    min_reviewers      INTEGER     NOT NULL     DEFAULT 1,
+    selection_strategy VARCHAR(32) NOT NULL     DEFAULT '',
+    created_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (team_id) REFERENCES teams (id)
+        ON DELETE CASCADE ON UPDATE CASCADE,
+    CHECK (reviewer_count >= 1),
+    CHECK (min_reviewers >= 0 AND min_reviewers <= reviewer_count)
+);
+
+CREATE OR REPLACE TRIGGER update_team_settings_updated_at
+    BEFORE UPDATE
+    ON team_settings
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,7 @@
+ALTER TABLE pull_requests
+    DROP CONSTRAINT IF EXISTS pull_requests_external_id_key,
+    DROP COLUMN IF EXISTS external_id;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_external_id_key,
+    DROP COLUMN IF EXISTS external_id;
@@ -0,0 +1,23 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS external_id VARCHAR(64);
+
+UPDATE users
+SET external_id = id::text
+WHERE external_id IS NULL;
+
+ALTER TABLE users
+    ALTER COLUMN external_id SET NOT NULL,
+    ADD CONSTRAINT users_external_id_key UNIQUE (external_id);
+
+
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS external_id VARCHAR(128);
+
+UPDATE pull_requests
+SET external_id = id::text
+WHERE external_id IS NULL;
+
+ALTER TABLE pull_requests
+    ALTER COLUMN external_id SET NOT NULL,
+    ADD CONSTRAINT pull_requests_external_id_key UNIQUE (external_id);
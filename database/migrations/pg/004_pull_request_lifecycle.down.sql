@@ -0,0 +1,7 @@
+UPDATE pull_requests
+SET status_id = (SELECT id FROM pull_request_statuses WHERE name = 'OPEN')
+WHERE status_id IN (SELECT id FROM pull_request_statuses WHERE name IN ('DRAFT', 'CLOSED'));
+
+DELETE
+FROM pull_request_statuses
+WHERE name IN ('DRAFT', 'CLOSED');
@@ -0,0 +1,4 @@
+INSERT INTO pull_request_statuses (name)
+VALUES ('DRAFT'),
+       ('CLOSED')
+ON CONFLICT (name) DO NOTHING;
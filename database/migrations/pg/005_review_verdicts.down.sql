@@ -0,0 +1,8 @@
+ALTER TABLE team_settings
+    DROP CONSTRAINT IF EXISTS team_settings_required_approvals_check,
+    DROP COLUMN IF EXISTS required_approvals;
+
+ALTER TABLE pull_request_reviewers
+    DROP CONSTRAINT IF EXISTS pull_request_reviewers_verdict_check,
+    DROP COLUMN IF EXISTS verdict_at,
+    DROP COLUMN IF EXISTS verdict;
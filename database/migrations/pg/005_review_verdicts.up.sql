@@ -0,0 +1,10 @@
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS verdict    VARCHAR(32) NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN IF NOT EXISTS verdict_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT pull_request_reviewers_verdict_check
+        CHECK (verdict IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'));
+
+ALTER TABLE team_settings
+    ADD COLUMN IF NOT EXISTS required_approvals INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT team_settings_required_approvals_check
+        CHECK (required_approvals >= 0 AND required_approvals <= reviewer_count);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS assignment_events;
+
+DROP FUNCTION IF EXISTS reject_assignment_event_update();
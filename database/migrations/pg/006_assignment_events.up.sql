@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS assignment_events
+(
+    id                   BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    pull_request_id      UUID        NOT NULL,
+    event_type           VARCHAR(16) NOT NULL,
+    reviewer_id          UUID,
+    previous_reviewer_id UUID,
+    actor                VARCHAR(128)             NOT NULL DEFAULT '',
+    reason               TEXT                     NOT NULL DEFAULT '',
+    created_at           TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id)
+        ON DELETE CASCADE ON UPDATE CASCADE,
+    CHECK (event_type IN ('ASSIGN', 'REASSIGN', 'UNASSIGN', 'MERGE'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_events_pr_id ON assignment_events (pull_request_id, id);
+
+CREATE OR REPLACE FUNCTION reject_assignment_event_update()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'assignment_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER assignment_events_append_only
+    BEFORE UPDATE
+    ON assignment_events
+    FOR EACH ROW
+EXECUTE FUNCTION reject_assignment_event_update();
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions
+(
+    id          UUID PRIMARY KEY                  DEFAULT gen_random_uuid(),
+    url         TEXT                     NOT NULL,
+    secret      TEXT                     NOT NULL,
+    event_types TEXT[]                   NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE TRIGGER update_webhook_subscriptions_updated_at
+    BEFORE UPDATE
+    ON webhook_subscriptions
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters
+(
+    id              UUID PRIMARY KEY                  DEFAULT gen_random_uuid(),
+    subscription_id UUID,
+    event_id        UUID                     NOT NULL,
+    event_type      VARCHAR(64)              NOT NULL,
+    url             TEXT                     NOT NULL,
+    payload         JSONB                    NOT NULL,
+    attempts        INTEGER                  NOT NULL,
+    last_error      TEXT                     NOT NULL DEFAULT '',
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions (id)
+        ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_created_at ON webhook_dead_letters (created_at DESC);
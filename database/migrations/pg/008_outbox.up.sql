@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox_events
+(
+    id           UUID PRIMARY KEY,
+    event_type   VARCHAR(64)              NOT NULL,
+    payload      JSONB                    NOT NULL,
+    occurred_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE,
+    attempts     INTEGER                  NOT NULL DEFAULT 0,
+    last_error   TEXT                     NOT NULL DEFAULT '',
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unpublished
+    ON outbox_events (occurred_at) WHERE published_at IS NULL;
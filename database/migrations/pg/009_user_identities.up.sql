@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    provider   VARCHAR(32)              NOT NULL,
+    login      VARCHAR(255)             NOT NULL,
+    user_id    UUID                     NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, login),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
@@ -0,0 +1,3 @@
+-- Keyset pagination for /pullRequest/list orders by (created_at, id).
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at_id ON pull_requests (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_at ON pull_requests (merged_at) WHERE merged_at IS NOT NULL;
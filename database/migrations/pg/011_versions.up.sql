@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
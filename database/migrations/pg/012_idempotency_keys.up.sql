@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    scope            VARCHAR(64)              NOT NULL,
+    key              VARCHAR(255)             NOT NULL,
+    request_hash     CHAR(64)                 NOT NULL,
+    -- NULL while the first request is in flight.
+    status_code      INTEGER,
+    response_headers JSONB                    NOT NULL DEFAULT '{}',
+    response_body    BYTEA,
+    created_at       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_unavailability
+(
+    id         UUID PRIMARY KEY                  DEFAULT gen_random_uuid(),
+    user_id    UUID                     NOT NULL,
+    starts_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason     TEXT                     NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_unavailability_user_id_ends_at ON user_unavailability (user_id, ends_at);
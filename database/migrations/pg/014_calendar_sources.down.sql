@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_unavailability_source_id;
+
+ALTER TABLE user_unavailability DROP COLUMN IF EXISTS source_id;
+
+DROP TABLE IF EXISTS calendar_sources;
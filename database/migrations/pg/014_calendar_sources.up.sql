@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS calendar_sources
+(
+    id             UUID PRIMARY KEY                  DEFAULT gen_random_uuid(),
+    user_id        UUID                     NOT NULL,
+    url            TEXT                     NOT NULL DEFAULT '',
+    last_synced_at TIMESTAMP WITH TIME ZONE,
+    last_error     TEXT                     NOT NULL DEFAULT '',
+    created_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, url),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+        ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE OR REPLACE TRIGGER update_calendar_sources_updated_at
+    BEFORE UPDATE
+    ON calendar_sources
+    FOR EACH ROW
+EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE user_unavailability
+    ADD COLUMN IF NOT EXISTS source_id UUID REFERENCES calendar_sources (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_user_unavailability_source_id ON user_unavailability (source_id);
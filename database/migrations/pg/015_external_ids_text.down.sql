@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ALTER COLUMN external_id TYPE VARCHAR(64);
+
+ALTER TABLE pull_requests
+    ALTER COLUMN external_id TYPE VARCHAR(128);
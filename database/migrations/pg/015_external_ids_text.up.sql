@@ -0,0 +1,6 @@
+-- Code host ids such as "group/subgroup/project!42" can be arbitrarily long.
+ALTER TABLE pull_requests
+    ALTER COLUMN external_id TYPE TEXT;
+
+ALTER TABLE users
+    ALTER COLUMN external_id TYPE TEXT;
@@ -0,0 +1,3 @@
+-- A failed event is not retried before next_attempt_at.
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
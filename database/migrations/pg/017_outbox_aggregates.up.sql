@@ -0,0 +1,8 @@
+-- Events are ordered per pull request only, so a failing event holds back
+-- the later events of its own pull request and nothing else. Rows written
+-- before this migration have no aggregate and are ordered on their own.
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS aggregate_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate
+    ON outbox_events (aggregate_id, occurred_at) WHERE published_at IS NULL;
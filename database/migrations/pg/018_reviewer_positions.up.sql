@@ -0,0 +1,16 @@
+-- Reviewers keep their slot when one of them is replaced, so the list is
+-- ordered by position rather than assigned_at.
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS position INT;
+
+UPDATE pull_request_reviewers prr
+SET position = ranked.position
+FROM (SELECT pull_request_id,
+             reviewer_id,
+             ROW_NUMBER() OVER (PARTITION BY pull_request_id ORDER BY assigned_at, reviewer_id) - 1 AS position
+      FROM pull_request_reviewers) ranked
+WHERE prr.pull_request_id = ranked.pull_request_id
+  AND prr.reviewer_id = ranked.reviewer_id;
+
+ALTER TABLE pull_request_reviewers
+    ALTER COLUMN position SET NOT NULL;
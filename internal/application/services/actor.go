@@ -0,0 +1,16 @@
+package services
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor attaches the identity of whoever triggered the request, so that
+// audit records can attribute changes to it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidUnavailability  = errors.New("invalid unavailability period")
+	ErrUnavailabilityNotFound = errors.New("unavailability period not found")
+)
+
+// DefaultAvailabilityService manages the periods in which users are skipped
+// by reviewer selection, independently of their is_active flag. Periods are
+// entered directly or imported from calendar feeds.
+type DefaultAvailabilityService struct {
+	userRepo  repositories.User
+	periods   repositories.Unavailability
+	sources   repositories.CalendarSource
+	txManager repositories.TxManager
+	fetcher   CalendarFetcher
+}
+
+func NewDefaultAvailabilityService(
+	userRepo repositories.User,
+	periods repositories.Unavailability,
+	sources repositories.CalendarSource,
+	txManager repositories.TxManager,
+	fetcher CalendarFetcher,
+) *DefaultAvailabilityService {
+	return &DefaultAvailabilityService{
+		userRepo:  userRepo,
+		periods:   periods,
+		sources:   sources,
+		txManager: txManager,
+		fetcher:   fetcher,
+	}
+}
+
+func (s *DefaultAvailabilityService) AddUnavailability(ctx context.Context, req dtos.UnavailabilityCreateRequestDTO) (*dtos.UnavailabilityDTO, error) {
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		return nil, fmt.Errorf("%w: startsAt and endsAt are required", ErrInvalidUnavailability)
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, fmt.Errorf("%w: endsAt must be after startsAt", ErrInvalidUnavailability)
+	}
+
+	user, err := s.findUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	period := &models.UserUnavailability{
+		UserID:   user.ID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	}
+	if err := s.periods.Create(ctx, period); err != nil {
+		return nil, fmt.Errorf("create unavailability: %w", err)
+	}
+
+	dto := convertUnavailabilityToDTO(user.ExternalID, period)
+	return &dto, nil
+}
+
+func (s *DefaultAvailabilityService) ListUnavailability(ctx context.Context, userID string) (*dtos.UnavailabilityListResponseDTO, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.periods.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list unavailability for %s: %w", userID, err)
+	}
+
+	resp := &dtos.UnavailabilityListResponseDTO{
+		UserID:         user.ExternalID,
+		Unavailability: make([]dtos.UnavailabilityDTO, 0, len(periods)),
+	}
+	for _, p := range periods {
+		resp.Unavailability = append(resp.Unavailability, convertUnavailabilityToDTO(user.ExternalID, p))
+	}
+	return resp, nil
+}
+
+func (s *DefaultAvailabilityService) DeleteUnavailability(ctx context.Context, id string) error {
+	periodID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrUnavailabilityNotFound
+	}
+
+	err = s.periods.DeleteByID(ctx, periodID)
+	if errors.Is(err, repositories.ErrUnavailabilityNotFound) {
+		return ErrUnavailabilityNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("delete unavailability: %w", err)
+	}
+	return nil
+}
+
+func (s *DefaultAvailabilityService) findUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByExternalID(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find user %s: %w", userID, err)
+	}
+	return user, nil
+}
+
+func convertUnavailabilityToDTO(userID string, p *models.UserUnavailability) dtos.UnavailabilityDTO {
+	dto := dtos.UnavailabilityDTO{
+		ID:        p.ID.String(),
+		UserID:    userID,
+		StartsAt:  p.StartsAt,
+		EndsAt:    p.EndsAt,
+		Reason:    p.Reason,
+		CreatedAt: p.CreatedAt,
+	}
+	if p.SourceID != nil {
+		dto.SourceID = p.SourceID.String()
+	}
+	return dto
+}
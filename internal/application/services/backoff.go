@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff computes exponential retry delays with jitter. It is safe for
+// concurrent use.
+type Backoff struct {
+	base time.Duration
+	max  time.Duration
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewBackoff returns a Backoff starting at base and capped at max. A nil rnd
+// is replaced by a time-seeded source.
+func NewBackoff(base time.Duration, max time.Duration, rnd *rand.Rand) *Backoff {
+	return &Backoff{base: base, max: max, rnd: orNewRand(rnd)}
+}
+
+// Delay doubles the base delay per failed attempt up to the maximum and adds
+// up to 20% jitter so that retries from many callers spread out.
+func (b *Backoff) Delay(attempt int) time.Duration {
+	delay := b.base
+	for i := 1; i < attempt && delay < b.max; i++ {
+		delay *= 2
+	}
+	delay = min(delay, b.max)
+
+	b.mu.Lock()
+	jitter := time.Duration(b.rnd.Int63n(int64(delay)/5 + 1))
+	b.mu.Unlock()
+
+	return delay + jitter
+}
+
+// Wait sleeps for Delay(attempt), returning ctx's error if it ends first.
+func (b *Backoff) Wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(b.Delay(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
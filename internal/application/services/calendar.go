@@ -0,0 +1,277 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/calendar"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCalendar        = errors.New("invalid calendar")
+	ErrCalendarFetchFailed    = errors.New("calendar feed could not be fetched")
+	ErrCalendarSourceNotFound = errors.New("calendar source not found")
+)
+
+// calendarHorizon is how far ahead feed events are imported. Each sync moves
+// the window forward.
+const calendarHorizon = 365 * 24 * time.Hour
+
+// CalendarFetcher downloads an iCalendar feed.
+type CalendarFetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// AddCalendarSource subscribes a user to a feed and imports it right away, so
+// an unreachable or malformed feed is rejected instead of stored.
+func (s *DefaultAvailabilityService) AddCalendarSource(ctx context.Context, req dtos.CalendarSourceCreateRequestDTO) (*dtos.CalendarImportResponseDTO, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "webcal") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) or webcal URL", ErrInvalidCalendar)
+	}
+
+	user, err := s.findUser(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := s.fetchCalendar(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &models.CalendarSource{UserID: user.ID, URL: req.URL}
+	return s.importCalendarResponse(ctx, user.ExternalID, source, cal)
+}
+
+// ImportCalendar replaces the periods of the user's uploaded calendar with
+// the events in data.
+func (s *DefaultAvailabilityService) ImportCalendar(ctx context.Context, userID string, data []byte) (*dtos.CalendarImportResponseDTO, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := calendar.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+
+	source := &models.CalendarSource{UserID: user.ID}
+	return s.importCalendarResponse(ctx, user.ExternalID, source, cal)
+}
+
+func (s *DefaultAvailabilityService) ListCalendarSources(ctx context.Context, userID string) (*dtos.CalendarSourceListResponseDTO, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := s.sources.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list calendar sources for %s: %w", userID, err)
+	}
+
+	resp := &dtos.CalendarSourceListResponseDTO{
+		UserID:  user.ExternalID,
+		Sources: make([]dtos.CalendarSourceDTO, 0, len(sources)),
+	}
+	for _, src := range sources {
+		resp.Sources = append(resp.Sources, convertCalendarSourceToDTO(user.ExternalID, src))
+	}
+	return resp, nil
+}
+
+// DeleteCalendarSource removes a source together with the periods imported
+// from it.
+func (s *DefaultAvailabilityService) DeleteCalendarSource(ctx context.Context, id string) error {
+	sourceID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrCalendarSourceNotFound
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.periods.DeleteBySourceID(ctx, sourceID); err != nil {
+			return fmt.Errorf("delete imported unavailability: %w", err)
+		}
+		err := s.sources.DeleteByID(ctx, sourceID)
+		if errors.Is(err, repositories.ErrCalendarSourceNotFound) {
+			return ErrCalendarSourceNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("delete calendar source: %w", err)
+		}
+		return nil
+	})
+}
+
+// SyncCalendars re-imports every URL source. A feed that cannot be fetched or
+// parsed keeps its previous periods and records the error on the source.
+func (s *DefaultAvailabilityService) SyncCalendars(ctx context.Context) error {
+	sources, err := s.sources.FindWithURL(ctx)
+	if err != nil {
+		return fmt.Errorf("find calendar sources to sync: %w", err)
+	}
+
+	for _, source := range sources {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := s.syncSource(ctx, source); err != nil {
+			log.Printf("calendar sync: source %s: %v", source.ID, err)
+		}
+	}
+	return nil
+}
+
+// RunCalendarSync calls SyncCalendars every interval until ctx is cancelled.
+func (s *DefaultAvailabilityService) RunCalendarSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.SyncCalendars(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("calendar sync: %v", err)
+		}
+	}
+}
+
+func (s *DefaultAvailabilityService) syncSource(ctx context.Context, source *models.CalendarSource) error {
+	cal, err := s.fetchCalendar(ctx, source.URL)
+	if err != nil {
+		now := time.Now()
+		source.LastSyncedAt = &now
+		source.LastError = err.Error()
+		if updateErr := s.sources.Update(ctx, source); updateErr != nil {
+			return fmt.Errorf("%w; record sync error: %w", err, updateErr)
+		}
+		return err
+	}
+
+	_, err = s.importCalendar(ctx, source, cal)
+	return err
+}
+
+func (s *DefaultAvailabilityService) fetchCalendar(ctx context.Context, feedURL string) (*calendar.Calendar, error) {
+	data, err := s.fetcher.Fetch(ctx, feedURL)
+	if err != nil {
+		log.Printf("calendar fetch %s: %v", redactURL(feedURL), err)
+		return nil, fmt.Errorf("%w: %s", ErrCalendarFetchFailed, fetchFailureReason(err))
+	}
+	cal, err := calendar.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	return cal, nil
+}
+
+// fetchFailureReason describes a fetch error without echoing transport
+// details, which would let callers probe the network the server sits on.
+func fetchFailureReason(err error) string {
+	var statusErr *calendar.StatusError
+	switch {
+	case errors.Is(err, calendar.ErrForbiddenDestination):
+		return calendar.ErrForbiddenDestination.Error()
+	case errors.Is(err, calendar.ErrFeedTooLarge):
+		return calendar.ErrFeedTooLarge.Error()
+	case errors.As(err, &statusErr):
+		return statusErr.Error()
+	default:
+		return "feed server is unreachable"
+	}
+}
+
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	return u.Redacted()
+}
+
+func (s *DefaultAvailabilityService) importCalendarResponse(
+	ctx context.Context,
+	userID string,
+	source *models.CalendarSource,
+	cal *calendar.Calendar,
+) (*dtos.CalendarImportResponseDTO, error) {
+	imported, err := s.importCalendar(ctx, source, cal)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dtos.CalendarImportResponseDTO{
+		Source:   convertCalendarSourceToDTO(userID, source),
+		Imported: imported,
+	}
+	for _, skipped := range cal.Skipped {
+		resp.Skipped = append(resp.Skipped, skipped.Error())
+	}
+	return resp, nil
+}
+
+// importCalendar replaces the periods of source, creating the source if
+// needed, with the events that have not ended yet and start within
+// calendarHorizon. It returns the number of periods imported.
+func (s *DefaultAvailabilityService) importCalendar(ctx context.Context, source *models.CalendarSource, cal *calendar.Calendar) (int, error) {
+	now := time.Now()
+	occurrences := cal.Occurrences(now, now.Add(calendarHorizon))
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.sources.Upsert(ctx, source); err != nil {
+			return fmt.Errorf("save calendar source: %w", err)
+		}
+		if err := s.periods.DeleteBySourceID(ctx, source.ID); err != nil {
+			return fmt.Errorf("delete previously imported unavailability: %w", err)
+		}
+		for _, o := range occurrences {
+			period := &models.UserUnavailability{
+				UserID:   source.UserID,
+				StartsAt: o.Start,
+				EndsAt:   o.End,
+				Reason:   o.Summary,
+				SourceID: &source.ID,
+			}
+			if err := s.periods.Create(ctx, period); err != nil {
+				return fmt.Errorf("import unavailability: %w", err)
+			}
+		}
+
+		source.LastSyncedAt = &now
+		source.LastError = ""
+		if err := s.sources.Update(ctx, source); err != nil {
+			return fmt.Errorf("update calendar source: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(occurrences), nil
+}
+
+func convertCalendarSourceToDTO(userID string, src *models.CalendarSource) dtos.CalendarSourceDTO {
+	return dtos.CalendarSourceDTO{
+		ID:           src.ID.String(),
+		UserID:       userID,
+		URL:          src.URL,
+		LastSyncedAt: src.LastSyncedAt,
+		LastError:    src.LastError,
+		CreatedAt:    src.CreatedAt,
+	}
+}
@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/calendar"
+	"pullrequest-manager/internal/infrastructure/database/memory"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// feedServer serves a one-event feed starting tomorrow, or fails with status
+// once failStatus is set.
+type feedServer struct {
+	failStatus atomic.Int32
+}
+
+func (f *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status := f.failStatus.Load(); status != 0 {
+		w.WriteHeader(int(status))
+		return
+	}
+	start := time.Now().UTC().Add(24 * time.Hour).Format("20060102T150405Z")
+	fmt.Fprintf(w, "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:trip\r\nSUMMARY:Trip\r\nDTSTART:%s\r\nDURATION:P2D\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n", start)
+}
+
+func newAvailabilityService(t *testing.T, fetcher services.CalendarFetcher) (*services.DefaultAvailabilityService, *memory.CalendarSourceRepository) {
+	t.Helper()
+
+	users := memory.NewUserRepository()
+	if err := users.Create(context.Background(), &models.User{ExternalID: "u1", Username: "Alice", IsActive: true}); err != nil {
+		t.Fatal(err)
+	}
+	sources := memory.NewCalendarSourceRepository()
+	return services.NewDefaultAvailabilityService(users, memory.NewUnavailabilityRepository(), sources, memory.NewTxManager(), fetcher), sources
+}
+
+type fetcherFunc func(ctx context.Context, url string) ([]byte, error)
+
+func (f fetcherFunc) Fetch(ctx context.Context, url string) ([]byte, error) {
+	return f(ctx, url)
+}
+
+func TestAddCalendarSourceImportsFeed(t *testing.T) {
+	feed := &feedServer{}
+	srv := httptest.NewServer(feed)
+	defer srv.Close()
+
+	svc, _ := newAvailabilityService(t, calendar.NewHTTPFetcher(srv.Client()))
+	resp, err := svc.AddCalendarSource(context.Background(), dtos.CalendarSourceCreateRequestDTO{UserID: "u1", URL: srv.URL + "/trip.ics"})
+	if err != nil {
+		t.Fatalf("AddCalendarSource: %v", err)
+	}
+	if resp.Imported != 1 {
+		t.Errorf("Imported = %d, want 1", resp.Imported)
+	}
+}
+
+func TestSyncCalendarsRecordsFetchFailure(t *testing.T) {
+	feed := &feedServer{}
+	srv := httptest.NewServer(feed)
+	defer srv.Close()
+
+	svc, _ := newAvailabilityService(t, calendar.NewHTTPFetcher(srv.Client()))
+	ctx := context.Background()
+	if _, err := svc.AddCalendarSource(ctx, dtos.CalendarSourceCreateRequestDTO{UserID: "u1", URL: srv.URL}); err != nil {
+		t.Fatalf("AddCalendarSource: %v", err)
+	}
+
+	feed.failStatus.Store(http.StatusGone)
+	if err := svc.SyncCalendars(ctx); err != nil {
+		t.Fatalf("SyncCalendars: %v", err)
+	}
+
+	list, err := svc.ListCalendarSources(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Sources) != 1 || !strings.Contains(list.Sources[0].LastError, "status 410") {
+		t.Errorf("sources = %+v, want a status 410 error", list.Sources)
+	}
+}
+
+func TestAddCalendarSourceHidesTransportErrors(t *testing.T) {
+	tests := []struct {
+		fetchErr error
+		want     string
+	}{
+		{errors.New("dial tcp 10.0.0.5:5432: connect: connection refused"), "feed server is unreachable"},
+		{fmt.Errorf("%w: 10.0.0.5", calendar.ErrForbiddenDestination), calendar.ErrForbiddenDestination.Error()},
+		{calendar.ErrFeedTooLarge, calendar.ErrFeedTooLarge.Error()},
+	}
+	for _, tt := range tests {
+		svc, _ := newAvailabilityService(t, fetcherFunc(func(ctx context.Context, url string) ([]byte, error) {
+			return nil, tt.fetchErr
+		}))
+
+		_, err := svc.AddCalendarSource(context.Background(), dtos.CalendarSourceCreateRequestDTO{UserID: "u1", URL: "https://intranet.example/feed"})
+		if !errors.Is(err, services.ErrCalendarFetchFailed) {
+			t.Fatalf("AddCalendarSource = %v, want ErrCalendarFetchFailed", err)
+		}
+		if !strings.HasSuffix(err.Error(), tt.want) || strings.Contains(err.Error(), "10.0.0.5") {
+			t.Errorf("AddCalendarSource(%v) = %q, want reason %q", tt.fetchErr, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrIdentityNotLinked = errors.New("code host login is not linked to a user")
+	ErrInvalidIdentity   = errors.New("invalid user identity")
+)
+
+// CodeHostAction is a provider-neutral pull request webhook action. Provider
+// handlers translate their payloads into these.
+type CodeHostAction string
+
+const (
+	CodeHostActionOpened         CodeHostAction = "opened"
+	CodeHostActionMerged         CodeHostAction = "merged"
+	CodeHostActionClosed         CodeHostAction = "closed"
+	CodeHostActionReopened       CodeHostAction = "reopened"
+	CodeHostActionReadyForReview CodeHostAction = "ready_for_review"
+)
+
+const (
+	CodeHostStatusApplied = "applied"
+	CodeHostStatusIgnored = "ignored"
+)
+
+// maxTitleLength matches pull_requests.title.
+const maxTitleLength = 64
+
+type CodeHostPullRequestEvent struct {
+	Provider      string
+	Action        CodeHostAction
+	PullRequestID string
+	Title         string
+	AuthorLogin   string
+	Draft         bool
+}
+
+// DefaultCodeHostService applies code host pull request events to the
+// pull request lifecycle. Redelivered or out-of-scope events, transitions
+// the pull request's state does not allow, pull requests by authors without
+// a linked user and teams without anyone to review are reported as ignored
+// rather than failed, so the code host does not flag the hook.
+type DefaultCodeHostService struct {
+	prService  *DefaultPullRequestService
+	userRepo   repositories.User
+	identities repositories.UserIdentity
+}
+
+func NewDefaultCodeHostService(
+	prService *DefaultPullRequestService,
+	userRepo repositories.User,
+	identities repositories.UserIdentity,
+) *DefaultCodeHostService {
+	return &DefaultCodeHostService{
+		prService:  prService,
+		userRepo:   userRepo,
+		identities: identities,
+	}
+}
+
+func (s *DefaultCodeHostService) HandlePullRequestEvent(ctx context.Context, evt CodeHostPullRequestEvent) (*dtos.CodeHostEventResponseDTO, error) {
+	var (
+		pr  *dtos.PullRequestDTO
+		err error
+	)
+
+	switch evt.Action {
+	case CodeHostActionOpened:
+		var authorID string
+		authorID, err = s.ResolveLogin(ctx, evt.Provider, evt.AuthorLogin)
+		if errors.Is(err, ErrIdentityNotLinked) {
+			return ignored(err.Error()), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		create := s.prService.CreateWithReviewers
+		if evt.Draft {
+			create = s.prService.CreateDraftWithReviewers
+		}
+		pr, err = create(ctx, evt.PullRequestID, truncateTitle(evt.Title), authorID)
+		if errors.Is(err, ErrPRAlreadyExists) {
+			return ignored("pull request is already tracked"), nil
+		}
+	case CodeHostActionMerged:
+		pr, err = s.prService.RecordExternalMerge(ctx, evt.PullRequestID)
+	case CodeHostActionClosed:
+		pr, err = s.prService.ClosePullRequest(ctx, evt.PullRequestID)
+	case CodeHostActionReopened:
+		pr, err = s.prService.ReopenPullRequest(ctx, evt.PullRequestID)
+	case CodeHostActionReadyForReview:
+		pr, err = s.prService.PublishPullRequest(ctx, evt.PullRequestID)
+	default:
+		return ignored(fmt.Sprintf("action %q is not handled", evt.Action)), nil
+	}
+
+	switch {
+	case errors.Is(err, ErrPRNotFound):
+		return ignored("pull request is not tracked"), nil
+	case errors.Is(err, ErrNoReviewCandidates),
+		errors.Is(err, ErrPRAlreadyMerged),
+		errors.Is(err, ErrPRClosed),
+		errors.Is(err, ErrPRNotOpen),
+		errors.Is(err, models.ErrInvalidStatusTransition):
+		// The code host already moved on; there is nothing to apply.
+		return ignored(err.Error()), nil
+	case err != nil:
+		return nil, err
+	}
+
+	return &dtos.CodeHostEventResponseDTO{Status: CodeHostStatusApplied, Pr: pr}, nil
+}
+
+// ResolveLogin maps a code host login to the user's external ID. An explicit
+// identity link wins; otherwise a user whose external ID equals the login is
+// used.
+func (s *DefaultCodeHostService) ResolveLogin(ctx context.Context, provider string, login string) (string, error) {
+	if login == "" {
+		return "", fmt.Errorf("%w: empty %s login", ErrIdentityNotLinked, provider)
+	}
+
+	identity, err := s.identities.FindByLogin(ctx, provider, normalizeLogin(login))
+	switch {
+	case err == nil:
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return "", fmt.Errorf("find user linked to %s login %q: %w", provider, login, err)
+		}
+		return user.ExternalID, nil
+	case !errors.Is(err, repositories.ErrUserIdentityNotFound):
+		return "", fmt.Errorf("find %s identity %q: %w", provider, login, err)
+	}
+
+	user, err := s.userRepo.FindByExternalID(ctx, login)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return "", fmt.Errorf("%w: %s login %q", ErrIdentityNotLinked, provider, login)
+	}
+	if err != nil {
+		return "", fmt.Errorf("find user %q: %w", login, err)
+	}
+	return user.ExternalID, nil
+}
+
+func (s *DefaultCodeHostService) LinkIdentity(ctx context.Context, req dtos.UserIdentityDTO) (*dtos.UserIdentityDTO, error) {
+	if !slices.Contains(models.Providers, req.Provider) {
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrInvalidIdentity, req.Provider)
+	}
+
+	user, err := s.userRepo.FindByExternalID(ctx, req.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find user %s: %w", req.UserID, err)
+	}
+
+	identity := &models.UserIdentity{
+		Provider: req.Provider,
+		Login:    normalizeLogin(req.Login),
+		UserID:   user.ID,
+	}
+	if err := s.identities.Upsert(ctx, identity); err != nil {
+		return nil, fmt.Errorf("link identity: %w", err)
+	}
+
+	return &dtos.UserIdentityDTO{
+		UserID:   user.ExternalID,
+		Provider: identity.Provider,
+		Login:    identity.Login,
+	}, nil
+}
+
+// normalizeLogin lowercases logins; code hosts treat them case-insensitively.
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+func truncateTitle(title string) string {
+	if utf8.RuneCountInString(title) <= maxTitleLength {
+		return title
+	}
+	return string([]rune(title)[:maxTitleLength])
+}
+
+func ignored(reason string) *dtos.CodeHostEventResponseDTO {
+	return &dtos.CodeHostEventResponseDTO{Status: CodeHostStatusIgnored, Reason: reason}
+}
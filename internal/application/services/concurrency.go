@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/infrastructure/repositories"
+)
+
+var ErrPreconditionFailed = errors.New("resource version does not match If-Match")
+
+// maxConflictAttempts bounds how often a read-modify-write is rerun after
+// losing a race to a concurrent update.
+const maxConflictAttempts = 3
+
+type expectedVersionKey struct{}
+
+// WithExpectedVersion makes the next PR or team mutation fail with
+// ErrPreconditionFailed unless the stored version equals version.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, version)
+}
+
+// withoutExpectedVersion drops an If-Match version that was meant for another
+// resource than the ones about to be modified.
+func withoutExpectedVersion(ctx context.Context) context.Context {
+	return context.WithValue(ctx, expectedVersionKey{}, nil)
+}
+
+func hasExpectedVersion(ctx context.Context) bool {
+	_, ok := ctx.Value(expectedVersionKey{}).(int64)
+	return ok
+}
+
+func checkExpectedVersion(ctx context.Context, current int64) error {
+	expected, ok := ctx.Value(expectedVersionKey{}).(int64)
+	if !ok || expected == current {
+		return nil
+	}
+	return fmt.Errorf("%w: current version is %d", ErrPreconditionFailed, current)
+}
+
+// retryOnConflict reruns op while it fails with ErrConcurrentModification. op
+// must reload everything it modifies on each run.
+func retryOnConflict[T any](ctx context.Context, op func() (T, error)) (T, error) {
+	var (
+		result T
+		err    error
+	)
+	for attempt := 1; attempt <= maxConflictAttempts; attempt++ {
+		result, err = op()
+		if !errors.Is(err, repositories.ErrConcurrentModification) || ctx.Err() != nil {
+			return result, err
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventPullRequestCreated    = "pull_request.created"
+	EventPullRequestReassigned = "pull_request.reassigned"
+	EventPullRequestMerged     = "pull_request.merged"
+
+	EventPullRequestReviewersChanged = "pull_request.reviewers_changed"
+)
+
+var EventTypes = []string{
+	EventPullRequestCreated,
+	EventPullRequestReassigned,
+	EventPullRequestMerged,
+	EventPullRequestReviewersChanged,
+}
+
+// Event is a notification about a committed state change. Data holds the
+// JSON of the DTO the API returns for the operation. ID is stable across
+// redeliveries, so consumers can deduplicate on it.
+type Event struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+
+	// Attempt counts the outbox relay's tries at publishing the event,
+	// starting at 1; LastAttempt is set on the final try. Publishers that
+	// record undeliverable events do so on the last attempt instead of
+	// retrying themselves.
+	Attempt     int  `json:"-"`
+	LastAttempt bool `json:"-"`
+}
+
+// EventPublisher delivers events to interested parties. Publish returns nil
+// only once the event is delivered or durably recorded as undeliverable; on an
+// error the outbox keeps the event and publishes it again later.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// addPendingEvent queues an event on the PR so the repository writes it to the
+// outbox in the same transaction as the change itself.
+func addPendingEvent(pr *models.PullRequest, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", eventType, err)
+	}
+	pr.AddPendingEvent(models.OutboxEvent{
+		ID:         uuid.New(),
+		EventType:  eventType,
+		Payload:    payload,
+		OccurredAt: time.Now().UTC(),
+	})
+	return nil
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
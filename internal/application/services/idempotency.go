@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"time"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+const (
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// idempotencyLockTimeout is how long an in-flight reservation blocks
+	// retries before it is considered abandoned by a crashed request.
+	idempotencyLockTimeout = time.Minute
+)
+
+// DefaultIdempotencyService lets clients retry non-idempotent requests
+// safely: the first response for a key is stored and replayed for retries
+// with the same request until the TTL expires.
+type DefaultIdempotencyService struct {
+	keys repositories.IdempotencyKey
+	ttl  time.Duration
+}
+
+func NewDefaultIdempotencyService(keys repositories.IdempotencyKey, ttl time.Duration) *DefaultIdempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &DefaultIdempotencyService{keys: keys, ttl: ttl}
+}
+
+// Begin reserves key within scope for a request whose body hashes to
+// requestHash. It returns nil when the caller should process the request and
+// the stored record when the response must be replayed instead.
+func (s *DefaultIdempotencyService) Begin(ctx context.Context, scope string, key string, requestHash string) (*models.IdempotencyRecord, error) {
+	now := time.Now()
+	rec := &models.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+
+	existing, err := s.keys.Reserve(ctx, rec, now.Add(-idempotencyLockTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	switch {
+	case existing == nil:
+		return nil, nil
+	case existing.RequestHash != requestHash:
+		return nil, ErrIdempotencyKeyReused
+	case !existing.Completed():
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+// Complete stores the response for replay.
+func (s *DefaultIdempotencyService) Complete(ctx context.Context, scope string, key string, status int, headers map[string]string, body []byte) error {
+	return s.keys.Complete(ctx, &models.IdempotencyRecord{
+		Scope:           scope,
+		Key:             key,
+		StatusCode:      status,
+		ResponseHeaders: headers,
+		ResponseBody:    body,
+	})
+}
+
+// Release drops an in-flight reservation, so a retry is processed afresh.
+func (s *DefaultIdempotencyService) Release(ctx context.Context, scope string, key string) error {
+	return s.keys.Release(ctx, scope, key)
+}
+
+// RunCleanup deletes expired keys every interval until ctx is cancelled.
+func (s *DefaultIdempotencyService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.keys.DeleteExpired(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("idempotency cleanup: %v", err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"time"
+)
+
+// OutboxRelayOptions configures the relay. A failed event is retried after an
+// exponential backoff between BaseBackoff and MaxBackoff; with the defaults it
+// is given up on after roughly eight hours. A claimed batch is leased for
+// LeaseDuration; an event still unpublished after that may be claimed again.
+type OutboxRelayOptions struct {
+	PollInterval  time.Duration
+	BatchSize     int
+	MaxAttempts   int
+	BaseBackoff   time.Duration
+	MaxBackoff    time.Duration
+	LeaseDuration time.Duration
+}
+
+func DefaultOutboxRelayOptions() OutboxRelayOptions {
+	return OutboxRelayOptions{
+		PollInterval:  time.Second,
+		BatchSize:     100,
+		MaxAttempts:   20,
+		BaseBackoff:   time.Second,
+		MaxBackoff:    time.Hour,
+		LeaseDuration: 5 * time.Minute,
+	}
+}
+
+// OutboxRelay moves committed events from the outbox to the publishers. An
+// event is marked published only after every publisher delivered it or
+// recorded it as undeliverable, so delivery is at least once: a crash or a
+// failing publisher redelivers the event with the same ID.
+type OutboxRelay struct {
+	outbox     repositories.Outbox
+	publishers []EventPublisher
+	opts       OutboxRelayOptions
+	backoff    *Backoff
+}
+
+func NewOutboxRelay(outbox repositories.Outbox, publishers []EventPublisher, opts OutboxRelayOptions) *OutboxRelay {
+	defaults := DefaultOutboxRelayOptions()
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaults.BaseBackoff
+	}
+	if opts.MaxBackoff < opts.BaseBackoff {
+		opts.MaxBackoff = max(defaults.MaxBackoff, opts.BaseBackoff)
+	}
+	if opts.LeaseDuration <= 0 {
+		opts.LeaseDuration = defaults.LeaseDuration
+	}
+	return &OutboxRelay{
+		outbox:     outbox,
+		publishers: publishers,
+		opts:       opts,
+		backoff:    NewBackoff(opts.BaseBackoff, opts.MaxBackoff, nil),
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. A full batch is followed
+// immediately by the next one instead of waiting for the poll interval.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n, err := r.RelayOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %v", err)
+		}
+
+		next := r.opts.PollInterval
+		if err == nil && n == r.opts.BatchSize {
+			next = 0
+		}
+		timer.Reset(next)
+	}
+}
+
+// RelayOnce publishes a single batch and returns how many events went out.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	return r.outbox.Process(ctx, r.opts.BatchSize, r.opts.MaxAttempts, r.opts.LeaseDuration, r.backoff.Delay, r.publish)
+}
+
+// publish hands the event to every publisher. An event whose last attempt
+// fails stays unpublished in the outbox with its last error, to be replayed
+// by hand.
+func (r *OutboxRelay) publish(ctx context.Context, e *models.OutboxEvent) error {
+	err := r.publishAll(ctx, e)
+	if err != nil && ctx.Err() == nil && e.Attempts+1 >= r.opts.MaxAttempts {
+		log.Printf("outbox relay: giving up on %s event %s after %d attempts: %v",
+			e.EventType, e.ID, e.Attempts+1, err)
+	}
+	return err
+}
+
+func (r *OutboxRelay) publishAll(ctx context.Context, e *models.OutboxEvent) error {
+	event := Event{
+		ID:          e.ID,
+		Type:        e.EventType,
+		OccurredAt:  e.OccurredAt,
+		Data:        e.Payload,
+		Attempt:     e.Attempts + 1,
+		LastAttempt: e.Attempts+1 >= r.opts.MaxAttempts,
+	}
+	for _, p := range r.publishers {
+		if err := p.Publish(ctx, event); err != nil {
+			return fmt.Errorf("publish %s event %s: %w", e.EventType, e.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/database/memory"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type publisherFunc func(ctx context.Context, event services.Event) error
+
+func (f publisherFunc) Publish(ctx context.Context, event services.Event) error {
+	return f(ctx, event)
+}
+
+// newOutbox returns an outbox holding one event per type, written the way
+// the service writes them: together with a pull request.
+func newOutbox(t *testing.T, eventTypes ...string) *memory.OutboxRepository {
+	t.Helper()
+
+	outbox := memory.NewOutboxRepository()
+	prs := memory.NewPullRequestRepository(memory.NewStatusRepository(), outbox)
+
+	pr := &models.PullRequest{ExternalID: "pr-1", Title: "outbox", AuthorID: uuid.New()}
+	for i, eventType := range eventTypes {
+		pr.AddPendingEvent(models.OutboxEvent{
+			ID:         uuid.New(),
+			EventType:  eventType,
+			Payload:    []byte(`{}`),
+			OccurredAt: time.Now().Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+	if err := prs.Create(context.Background(), pr); err != nil {
+		t.Fatal(err)
+	}
+	return outbox
+}
+
+func TestOutboxRelayPublishesInOrder(t *testing.T) {
+	outbox := newOutbox(t, services.EventPullRequestCreated, services.EventPullRequestMerged)
+
+	var got []string
+	relay := services.NewOutboxRelay(outbox, []services.EventPublisher{
+		publisherFunc(func(ctx context.Context, event services.Event) error {
+			got = append(got, event.Type)
+			return nil
+		}),
+	}, services.OutboxRelayOptions{})
+
+	n, err := relay.RelayOnce(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("RelayOnce = %d, %v; want 2, nil", n, err)
+	}
+	if len(got) != 2 || got[0] != services.EventPullRequestCreated || got[1] != services.EventPullRequestMerged {
+		t.Errorf("published %v", got)
+	}
+
+	if n, _ := relay.RelayOnce(context.Background()); n != 0 {
+		t.Errorf("second RelayOnce published %d events, want 0", n)
+	}
+}
+
+func TestOutboxRelayKeepsFailedEventsUntilTheyArePublished(t *testing.T) {
+	outbox := newOutbox(t, services.EventPullRequestCreated, services.EventPullRequestMerged)
+
+	fail := true
+	var got []string
+	relay := services.NewOutboxRelay(outbox, []services.EventPublisher{
+		publisherFunc(func(ctx context.Context, event services.Event) error {
+			if fail {
+				return errors.New("receiver is down")
+			}
+			got = append(got, event.Type)
+			return nil
+		}),
+	}, services.OutboxRelayOptions{BaseBackoff: 20 * time.Millisecond, MaxBackoff: 20 * time.Millisecond})
+
+	if n, err := relay.RelayOnce(context.Background()); n != 0 || err != nil {
+		t.Fatalf("RelayOnce = %d, %v; want 0, nil", n, err)
+	}
+
+	// The failed event waits for its backoff and holds back the one after it.
+	fail = false
+	if n, _ := relay.RelayOnce(context.Background()); n != 0 {
+		t.Fatalf("RelayOnce during backoff published %d events, want 0", n)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if n, _ := relay.RelayOnce(context.Background()); n != 2 {
+		t.Fatalf("RelayOnce after backoff published %d events, want 2", n)
+	}
+	if len(got) != 2 || got[0] != services.EventPullRequestCreated {
+		t.Errorf("published %v", got)
+	}
+}
+
+func TestOutboxRelayGivesUpAfterMaxAttempts(t *testing.T) {
+	outbox := newOutbox(t, services.EventPullRequestCreated)
+
+	var attempts []string
+	relay := services.NewOutboxRelay(outbox, []services.EventPublisher{
+		publisherFunc(func(ctx context.Context, event services.Event) error {
+			attempts = append(attempts, fmt.Sprintf("%d/%t", event.Attempt, event.LastAttempt))
+			return errors.New("receiver is down")
+		}),
+	}, services.OutboxRelayOptions{MaxAttempts: 2, BaseBackoff: time.Nanosecond, MaxBackoff: time.Nanosecond})
+
+	for i := 0; i < 4; i++ {
+		relay.RelayOnce(context.Background())
+		time.Sleep(time.Millisecond)
+	}
+	// Publishers learn which try is the last so they can record the event.
+	if got := strings.Join(attempts, " "); got != "1/false 2/true" {
+		t.Errorf("attempts = %s, want 1/false 2/true", got)
+	}
+}
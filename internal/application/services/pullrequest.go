@@ -4,32 +4,42 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
 	"pullrequest-manager/internal/domain/models"
-	"pullrequest-manager/internal/infrastructure/database/pg"
 	"pullrequest-manager/internal/infrastructure/dtos"
 	"pullrequest-manager/internal/infrastructure/repositories"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 var (
-	ErrAuthorNotFound     = errors.New("author not found")
-	ErrTeamNotFound       = errors.New("team not found")
-	ErrPRAlreadyExists    = errors.New("pull request already exists")
-	ErrPRNotFound         = errors.New("pull request not found")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserNotReviewer    = errors.New("user is not a reviewer")
-	ErrNoReviewCandidates = errors.New("no users available to review")
-	ErrPRAlreadyMerged    = errors.New("cannot change PR state because already merged")
+	ErrAuthorNotFound      = errors.New("author not found")
+	ErrTeamNotFound        = errors.New("team not found")
+	ErrTeamAlreadyExists   = errors.New("team_name already exists")
+	ErrPRAlreadyExists     = errors.New("pull request already exists")
+	ErrPRNotFound          = errors.New("pull request not found")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserNotReviewer     = errors.New("user is not a reviewer")
+	ErrNoReviewCandidates  = errors.New("no users available to review")
+	ErrPRAlreadyMerged     = errors.New("cannot change PR state because already merged")
+	ErrPRClosed            = errors.New("cannot change reviewers because PR is closed")
+	ErrInvalidTeamSettings = errors.New("invalid team settings")
+	ErrInvalidVerdict      = errors.New("invalid review verdict")
+	ErrPRNotOpen           = errors.New("pull request is not open for review")
+	ErrPRNotApproved       = errors.New("pull request does not have the required approvals")
+	ErrInvalidListQuery    = errors.New("invalid pull request list query")
 )
 
 type DefaultPullRequestService struct {
-	userRepo   repositories.User
-	prRepo     repositories.PullRequest
-	teamRepo   repositories.Team
-	statusRepo repositories.Status
+	userRepo     repositories.User
+	prRepo       repositories.PullRequest
+	teamRepo     repositories.Team
+	statusRepo   repositories.Status
+	settingsRepo repositories.TeamSettings
+	selector     ReviewerSelector
+	txManager    repositories.TxManager
+	unavailable  repositories.Unavailability
 }
 
 func NewDefaultPullRequestService(
@@ -37,34 +47,85 @@ func NewDefaultPullRequestService(
 	prRepo repositories.PullRequest,
 	teamRepo repositories.Team,
 	statusRepo repositories.Status,
+	settingsRepo repositories.TeamSettings,
+	selector ReviewerSelector,
+	txManager repositories.TxManager,
+	unavailable repositories.Unavailability,
 ) (*DefaultPullRequestService, error) {
+	if selector == nil {
+		return nil, errors.New("reviewer selector is required")
+	}
+	if txManager == nil {
+		return nil, errors.New("transaction manager is required")
+	}
+	if unavailable == nil {
+		return nil, errors.New("unavailability repository is required")
+	}
 	return &DefaultPullRequestService{
-		userRepo:   userRepo,
-		prRepo:     prRepo,
-		teamRepo:   teamRepo,
-		statusRepo: statusRepo,
+		userRepo:     userRepo,
+		prRepo:       prRepo,
+		teamRepo:     teamRepo,
+		statusRepo:   statusRepo,
+		settingsRepo: settingsRepo,
+		selector:     selector,
+		txManager:    txManager,
+		unavailable:  unavailable,
 	}, nil
 }
 
-func (s *DefaultPullRequestService) CreateWithReviewers(ctx context.Context, prID uuid.UUID, prName string, authorID uuid.UUID) (*dtos.PullRequestDTO, error) {
-	existing, err := s.prRepo.FindByID(ctx, prID)
-	if err != nil && !errors.Is(err, pg.ErrPullRequestNotFound) {
+func (s *DefaultPullRequestService) CreateWithReviewers(ctx context.Context, prID string, prName string, authorID string) (*dtos.PullRequestDTO, error) {
+	return s.createWithReviewers(ctx, prID, prName, authorID, models.StatusOpen)
+}
+
+func (s *DefaultPullRequestService) CreateDraftWithReviewers(ctx context.Context, prID string, prName string, authorID string) (*dtos.PullRequestDTO, error) {
+	return s.createWithReviewers(ctx, prID, prName, authorID, models.StatusDraft)
+}
+
+// createWithReviewers runs reviewer selection and the insert in one
+// transaction, so a failed insert leaves no partial PR behind. The team is not
+// locked: a concurrent create may change reviewer workloads between selection
+// and commit, so load-based strategies are best effort.
+func (s *DefaultPullRequestService) createWithReviewers(
+	ctx context.Context,
+	prID string,
+	prName string,
+	authorID string,
+	initialStatus models.StatusName,
+) (*dtos.PullRequestDTO, error) {
+	var pr *dtos.PullRequestDTO
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.createPullRequest(ctx, prID, prName, authorID, initialStatus)
+		return err
+	})
+	return pr, err
+}
+
+func (s *DefaultPullRequestService) createPullRequest(
+	ctx context.Context,
+	prID string,
+	prName string,
+	authorID string,
+	initialStatus models.StatusName,
+) (*dtos.PullRequestDTO, error) {
+	existing, err := s.prRepo.FindByExternalID(ctx, prID)
+	if err != nil && !errors.Is(err, repositories.ErrPullRequestNotFound) {
 		return nil, fmt.Errorf("check for existing PR: %w", err)
 	}
 	if existing != nil {
 		return nil, ErrPRAlreadyExists
 	}
 
-	_, err = s.userRepo.FindByID(ctx, authorID)
-	if errors.Is(err, pg.ErrUserNotFound) {
+	author, err := s.userRepo.FindByExternalID(ctx, authorID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
 		return nil, ErrAuthorNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("find author: %w", err)
 	}
 
-	team, err := s.teamRepo.FindByUserID(ctx, authorID)
-	if errors.Is(err, pg.ErrTeamNotFound) {
+	team, err := s.teamRepo.FindByUserID(ctx, author.ID)
+	if errors.Is(err, repositories.ErrTeamNotFound) {
 		return nil, ErrTeamNotFound
 	}
 	if err != nil {
@@ -73,11 +134,11 @@ func (s *DefaultPullRequestService) CreateWithReviewers(ctx context.Context, prI
 
 	var activeUsers []uuid.UUID
 	for _, uid := range team.UserIDs {
-		if uid == authorID {
+		if uid == author.ID {
 			continue
 		}
 		u, err := s.userRepo.FindByID(ctx, uid)
-		if errors.Is(err, pg.ErrUserNotFound) {
+		if errors.Is(err, repositories.ErrUserNotFound) {
 			continue
 		}
 		if err != nil {
@@ -88,64 +149,130 @@ func (s *DefaultPullRequestService) CreateWithReviewers(ctx context.Context, prI
 		}
 	}
 
-	if len(activeUsers) == 0 {
+	activeUsers, err = s.withoutUnavailable(ctx, activeUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.teamSettings(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(activeUsers) == 0 || len(activeUsers) < settings.MinReviewers {
 		return nil, ErrNoReviewCandidates
 	}
 
-	reviewers := chooseRandomUsers(activeUsers, 2)
+	reviewers, err := s.selector.Select(ctx, team, activeUsers, settings.ReviewerCount)
+	if err != nil {
+		return nil, fmt.Errorf("select reviewers: %w", err)
+	}
+	if len(reviewers) == 0 || len(reviewers) < settings.MinReviewers {
+		return nil, ErrNoReviewCandidates
+	}
 
 	statuses, err := s.statusRepo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get all statuses: %w", err)
 	}
-	var openStatusID uuid.UUID
-	for _, st := range statuses {
-		if st.Name == "OPEN" {
-			openStatusID = st.ID
-			break
-		}
-	}
-	if openStatusID == uuid.Nil {
-		return nil, fmt.Errorf("status 'OPEN' not found in database")
+	initialStatusID, err := findStatusID(statuses, initialStatus)
+	if err != nil {
+		return nil, err
 	}
 
 	newPR := &models.PullRequest{
-		ID:           prID,
+		ExternalID:   prID,
 		Title:        prName,
-		AuthorID:     authorID,
-		StatusID:     openStatusID,
+		AuthorID:     author.ID,
+		StatusID:     initialStatusID,
 		MergedAt:     nil,
 		ReviewersIDs: reviewers,
 	}
+	for _, rid := range reviewers {
+		newPR.RecordEvent(newAssignmentEvent(ctx, models.EventAssign, &rid, nil, ""))
+	}
 
-	if err := s.prRepo.Create(ctx, newPR); err != nil {
+	eventDTO, err := s.convertPullRequestToDTO(ctx, newPR, statuses)
+	if err != nil {
+		return nil, err
+	}
+	// The creation time is only known once the row is inserted; subscribers
+	// get the event's occurred_at instead.
+	eventDTO.CreatedAt = nil
+	if err := addPendingEvent(newPR, EventPullRequestCreated, dtos.PullRequestResponseDTO{Pr: *eventDTO}); err != nil {
+		return nil, err
+	}
+	if len(eventDTO.AssignedReviewers) > 0 {
+		changed := dtos.ReviewersChangedEventDTO{
+			PullRequestID: prID,
+			Added:         eventDTO.AssignedReviewers,
+			Removed:       []string{},
+		}
+		if err := addPendingEvent(newPR, EventPullRequestReviewersChanged, changed); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.prRepo.Create(ctx, newPR)
+	if errors.Is(err, repositories.ErrDuplicateKey) {
+		return nil, ErrPRAlreadyExists
+	}
+	if err != nil {
 		return nil, fmt.Errorf("create pull request: %w", err)
 	}
 
-	return convertPullRequestToDTO(newPR, statuses), nil
+	return s.convertPullRequestToDTO(ctx, newPR, statuses)
 }
 
-func (s *DefaultPullRequestService) ReassignReviewer(ctx context.Context, userID uuid.UUID, prID uuid.UUID) (*dtos.ReassignReviewerResponseDTO, error) {
-	pr, err := s.prRepo.FindByID(ctx, prID)
-	if errors.Is(err, pg.ErrPullRequestNotFound) {
+func (s *DefaultPullRequestService) ReassignReviewer(ctx context.Context, userID string, prID string, reason string) (*dtos.ReassignReviewerResponseDTO, error) {
+	return retryOnConflict(ctx, func() (*dtos.ReassignReviewerResponseDTO, error) {
+		var resp *dtos.ReassignReviewerResponseDTO
+		err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			resp, err = s.reassignReviewer(ctx, userID, prID, reason)
+			return err
+		})
+		return resp, err
+	})
+}
+
+func (s *DefaultPullRequestService) reassignReviewer(ctx context.Context, userID string, prID string, reason string) (*dtos.ReassignReviewerResponseDTO, error) {
+	pr, err := s.prRepo.FindByExternalID(ctx, prID)
+	if errors.Is(err, repositories.ErrPullRequestNotFound) {
 		return nil, ErrPRNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("find PR for reassignment: %w", err)
 	}
+	if err := checkExpectedVersion(ctx, pr.Version); err != nil {
+		return nil, err
+	}
 
 	status, err := s.statusRepo.FindByID(ctx, pr.StatusID)
-	if err != nil && !errors.Is(err, pg.ErrStatusNotFound) {
+	if err != nil && !errors.Is(err, repositories.ErrStatusNotFound) {
 		return nil, fmt.Errorf("find status for PR: %w", err)
 	}
-	if status != nil && status.Name == "MERGED" {
-		return nil, ErrPRAlreadyMerged
+	if status != nil {
+		switch status.StatusName() {
+		case models.StatusMerged:
+			return nil, ErrPRAlreadyMerged
+		case models.StatusClosed:
+			return nil, ErrPRClosed
+		}
+	}
+
+	reviewer, err := s.userRepo.FindByExternalID(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find reviewer %s: %w", userID, err)
 	}
 
 	isReviewer := false
 	reviewerIndex := -1
 	for i, rid := range pr.ReviewersIDs {
-		if rid == userID {
+		if rid == reviewer.ID {
 			isReviewer = true
 			reviewerIndex = i
 			break
@@ -155,8 +282,8 @@ func (s *DefaultPullRequestService) ReassignReviewer(ctx context.Context, userID
 		return nil, ErrUserNotReviewer
 	}
 
-	team, err := s.teamRepo.FindByUserID(ctx, userID)
-	if errors.Is(err, pg.ErrTeamNotFound) {
+	team, err := s.teamRepo.FindByUserID(ctx, reviewer.ID)
+	if errors.Is(err, repositories.ErrTeamNotFound) {
 		return nil, ErrTeamNotFound
 	}
 	if err != nil {
@@ -169,7 +296,7 @@ func (s *DefaultPullRequestService) ReassignReviewer(ctx context.Context, userID
 			continue
 		}
 		u, err := s.userRepo.FindByID(ctx, uid)
-		if errors.Is(err, pg.ErrUserNotFound) {
+		if errors.Is(err, repositories.ErrUserNotFound) {
 			continue
 		}
 		if err != nil {
@@ -180,86 +307,339 @@ func (s *DefaultPullRequestService) ReassignReviewer(ctx context.Context, userID
 		}
 	}
 
+	candidates, err = s.withoutUnavailable(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
 	if len(candidates) == 0 {
 		return nil, ErrNoReviewCandidates
 	}
 
-	newReviewer := candidates[rand.Intn(len(candidates))]
+	selected, err := s.selector.Select(ctx, team, candidates, 1)
+	if err != nil {
+		return nil, fmt.Errorf("select replacement reviewer: %w", err)
+	}
+	if len(selected) == 0 {
+		return nil, ErrNoReviewCandidates
+	}
+	newReviewer := selected[0]
 	pr.ReviewersIDs[reviewerIndex] = newReviewer
+	delete(pr.Reviews, reviewer.ID)
+	pr.RecordEvent(newAssignmentEvent(ctx, models.EventReassign, &newReviewer, &reviewer.ID, reason))
+
+	statuses, err := s.statusRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get statuses for response DTO: %w", err)
+	}
+	prDTO, err := s.convertPullRequestToDTO(ctx, pr, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	replacedBy, err := s.externalUserID(ctx, newReviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dtos.ReassignReviewerResponseDTO{
+		Pr:         *prDTO,
+		ReplacedBy: replacedBy,
+	}
+	if err := addPendingEvent(pr, EventPullRequestReassigned, resp); err != nil {
+		return nil, err
+	}
+	changed := dtos.ReviewersChangedEventDTO{
+		PullRequestID: prID,
+		Added:         []string{replacedBy},
+		Removed:       []string{reviewer.ExternalID},
+	}
+	if err := addPendingEvent(pr, EventPullRequestReviewersChanged, changed); err != nil {
+		return nil, err
+	}
 
 	if err := s.prRepo.Update(ctx, pr); err != nil {
 		return nil, fmt.Errorf("update pull request after reassignment: %w", err)
 	}
+	resp.Pr.Version = pr.Version
+
+	return resp, nil
+}
+
+func (s *DefaultPullRequestService) MarkAsMerged(ctx context.Context, prID string) (*dtos.PullRequestDTO, error) {
+	return s.changeStatus(ctx, prID, models.StatusMerged, nil)
+}
+
+// externalMergeReason is recorded on merges reported by a code host.
+const externalMergeReason = "merged on code host"
+
+// RecordExternalMerge marks a PR as merged because its code host merged it.
+// The merge has already happened there, so the team's approval policy is not
+// enforced.
+func (s *DefaultPullRequestService) RecordExternalMerge(ctx context.Context, prID string) (*dtos.PullRequestDTO, error) {
+	return retryOnConflict(ctx, func() (*dtos.PullRequestDTO, error) {
+		return s.tryChangeStatus(ctx, prID, models.StatusMerged, nil, false)
+	})
+}
+
+// SubmitReview records the reviewer's verdict on an OPEN pull request,
+// replacing any verdict they submitted before.
+func (s *DefaultPullRequestService) SubmitReview(ctx context.Context, prID string, userID string, verdict models.ReviewVerdict) (*dtos.PullRequestDTO, error) {
+	if !verdict.IsValid() || verdict == models.VerdictPending {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVerdict, verdict)
+	}
+
+	return retryOnConflict(ctx, func() (*dtos.PullRequestDTO, error) {
+		return s.submitReview(ctx, prID, userID, verdict)
+	})
+}
+
+func (s *DefaultPullRequestService) submitReview(ctx context.Context, prID string, userID string, verdict models.ReviewVerdict) (*dtos.PullRequestDTO, error) {
+	pr, err := s.prRepo.FindByExternalID(ctx, prID)
+	if errors.Is(err, repositories.ErrPullRequestNotFound) {
+		return nil, ErrPRNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find PR for review: %w", err)
+	}
+	if err := checkExpectedVersion(ctx, pr.Version); err != nil {
+		return nil, err
+	}
 
 	statuses, err := s.statusRepo.FindAll(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("get statuses for response DTO: %w", err)
+		return nil, fmt.Errorf("get all statuses: %w", err)
+	}
+	switch models.StatusName(convertStatusToStringMap(statuses)[pr.StatusID]) {
+	case models.StatusOpen:
+	case models.StatusMerged:
+		return nil, ErrPRAlreadyMerged
+	default:
+		return nil, ErrPRNotOpen
 	}
-	prDTO := convertPullRequestToDTO(pr, statuses)
 
-	return &dtos.ReassignReviewerResponseDTO{
-		Pr:         *prDTO,
-		ReplacedBy: newReviewer,
-	}, nil
+	reviewer, err := s.userRepo.FindByExternalID(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find reviewer %s: %w", userID, err)
+	}
+	if !contains(pr.ReviewersIDs, reviewer.ID) {
+		return nil, ErrUserNotReviewer
+	}
+
+	pr.SetVerdict(reviewer.ID, verdict, time.Now())
+	if err := s.prRepo.Update(ctx, pr); err != nil {
+		return nil, fmt.Errorf("update pull request after review: %w", err)
+	}
+
+	return s.convertPullRequestToDTO(ctx, pr, statuses)
+}
+
+func (s *DefaultPullRequestService) ClosePullRequest(ctx context.Context, prID string) (*dtos.PullRequestDTO, error) {
+	return s.changeStatus(ctx, prID, models.StatusClosed, nil)
 }
 
-func (s *DefaultPullRequestService) MarkAsMerged(ctx context.Context, prID uuid.UUID) (*dtos.PullRequestDTO, error) {
-	pr, err := s.prRepo.FindByID(ctx, prID)
-	if errors.Is(err, pg.ErrPullRequestNotFound) {
+func (s *DefaultPullRequestService) ReopenPullRequest(ctx context.Context, prID string) (*dtos.PullRequestDTO, error) {
+	return s.changeStatus(ctx, prID, models.StatusOpen, []models.StatusName{models.StatusClosed})
+}
+
+func (s *DefaultPullRequestService) PublishPullRequest(ctx context.Context, prID string) (*dtos.PullRequestDTO, error) {
+	return s.changeStatus(ctx, prID, models.StatusOpen, []models.StatusName{models.StatusDraft})
+}
+
+// changeStatus moves a PR along the lifecycle state machine, optionally only
+// from the given source states. Requesting the state the PR is already in is a
+// no-op, which keeps merge idempotent.
+func (s *DefaultPullRequestService) changeStatus(
+	ctx context.Context,
+	prID string,
+	to models.StatusName,
+	allowedFrom []models.StatusName,
+) (*dtos.PullRequestDTO, error) {
+	return retryOnConflict(ctx, func() (*dtos.PullRequestDTO, error) {
+		return s.tryChangeStatus(ctx, prID, to, allowedFrom, true)
+	})
+}
+
+func (s *DefaultPullRequestService) tryChangeStatus(
+	ctx context.Context,
+	prID string,
+	to models.StatusName,
+	allowedFrom []models.StatusName,
+	enforceApprovals bool,
+) (*dtos.PullRequestDTO, error) {
+	pr, err := s.prRepo.FindByExternalID(ctx, prID)
+	if errors.Is(err, repositories.ErrPullRequestNotFound) {
 		return nil, ErrPRNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("find PR to mark as merged: %w", err)
+		return nil, fmt.Errorf("find PR to change status: %w", err)
+	}
+	if err := checkExpectedVersion(ctx, pr.Version); err != nil {
+		return nil, err
 	}
 
 	statuses, err := s.statusRepo.FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("get all statuses: %w", err)
 	}
-	var mergedStatusID uuid.UUID
-	for _, st := range statuses {
-		if st.Name == "MERGED" {
-			mergedStatusID = st.ID
-			break
-		}
+	targetStatusID, err := findStatusID(statuses, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.StatusID == targetStatusID {
+		return s.convertPullRequestToDTO(ctx, pr, statuses)
 	}
-	if mergedStatusID == uuid.Nil {
-		return nil, fmt.Errorf("status 'MERGED' not found in database")
+
+	from := models.StatusName(convertStatusToStringMap(statuses)[pr.StatusID])
+	if allowedFrom != nil && !slices.Contains(allowedFrom, from) {
+		return nil, fmt.Errorf("%w: %s -> %s", models.ErrInvalidStatusTransition, from, to)
+	}
+	if err := from.ValidateTransition(to); err != nil {
+		return nil, err
+	}
+	if to == models.StatusMerged && enforceApprovals {
+		if err := s.checkApprovals(ctx, pr); err != nil {
+			return nil, err
+		}
 	}
 
-	if pr.StatusID == mergedStatusID {
-		return convertPullRequestToDTO(pr, statuses), nil
+	pr.StatusID = targetStatusID
+	if to == models.StatusMerged {
+		now := time.Now()
+		pr.MergedAt = &now
+		reason := ""
+		if !enforceApprovals {
+			reason = externalMergeReason
+		}
+		pr.RecordEvent(newAssignmentEvent(ctx, models.EventMerge, nil, nil, reason))
 	}
 
-	now := time.Now()
-	pr.StatusID = mergedStatusID
-	pr.MergedAt = &now
+	prDTO, err := s.convertPullRequestToDTO(ctx, pr, statuses)
+	if err != nil {
+		return nil, err
+	}
+	if to == models.StatusMerged {
+		if err := addPendingEvent(pr, EventPullRequestMerged, dtos.PullRequestResponseDTO{Pr: *prDTO}); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.prRepo.Update(ctx, pr); err != nil {
-		return nil, fmt.Errorf("update pull request to merged: %w", err)
+		return nil, fmt.Errorf("update pull request status to %s: %w", to, err)
 	}
+	prDTO.Version = pr.Version
 
-	return convertPullRequestToDTO(pr, statuses), nil
+	return prDTO, nil
 }
 
+// GetPullRequestHistory returns the PR's reviewer assignment timeline, oldest
+// event first.
+func (s *DefaultPullRequestService) GetPullRequestHistory(ctx context.Context, prID string) (*dtos.PullRequestHistoryDTO, error) {
+	pr, err := s.prRepo.FindByExternalID(ctx, prID)
+	if errors.Is(err, repositories.ErrPullRequestNotFound) {
+		return nil, ErrPRNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find PR for history: %w", err)
+	}
+
+	events, err := s.prRepo.FindAssignmentEvents(ctx, pr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get assignment events: %w", err)
+	}
+
+	history := &dtos.PullRequestHistoryDTO{
+		PullRequestID: pr.ExternalID,
+		Events:        make([]dtos.AssignmentEventDTO, 0, len(events)),
+	}
+	for _, e := range events {
+		eventDTO := dtos.AssignmentEventDTO{
+			Type:      string(e.Type),
+			Actor:     e.Actor,
+			Reason:    e.Reason,
+			CreatedAt: e.CreatedAt,
+		}
+		if e.ReviewerID != nil {
+			if eventDTO.ReviewerID, err = s.externalUserID(ctx, *e.ReviewerID); err != nil {
+				return nil, err
+			}
+		}
+		if e.PreviousReviewerID != nil {
+			if eventDTO.PreviousReviewerID, err = s.externalUserID(ctx, *e.PreviousReviewerID); err != nil {
+				return nil, err
+			}
+		}
+		history.Events = append(history.Events, eventDTO)
+	}
+
+	return history, nil
+}
+
+// checkApprovals enforces the author's team merge policy: enough approvals and
+// no outstanding change requests from the current reviewers.
+func (s *DefaultPullRequestService) checkApprovals(ctx context.Context, pr *models.PullRequest) error {
+	settings := models.DefaultTeamSettings(uuid.Nil)
+	team, err := s.teamRepo.FindByUserID(ctx, pr.AuthorID)
+	if err != nil && !errors.Is(err, repositories.ErrTeamNotFound) {
+		return fmt.Errorf("find team for author: %w", err)
+	}
+	if team != nil {
+		settings, err = s.teamSettings(ctx, team.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	approvals, changesRequested := pr.ApprovalState()
+	if changesRequested > 0 {
+		return fmt.Errorf("%w: %d reviewer(s) requested changes", ErrPRNotApproved, changesRequested)
+	}
+	if approvals < settings.RequiredApprovals {
+		return fmt.Errorf("%w: %d of %d approvals", ErrPRNotApproved, approvals, settings.RequiredApprovals)
+	}
+	return nil
+}
+
+// CreateTeam upserts the members and the roster in one transaction, so a
+// failure leaves neither orphaned users nor a partial roster behind.
 func (s *DefaultPullRequestService) CreateTeam(ctx context.Context, teamName string, members []dtos.TeamMemberDTO) error {
+	_, err := retryOnConflict(ctx, func() (struct{}, error) {
+		return struct{}{}, s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			return s.createTeam(ctx, teamName, members)
+		})
+	})
+	return err
+}
+
+func (s *DefaultPullRequestService) createTeam(ctx context.Context, teamName string, members []dtos.TeamMemberDTO) error {
 	existingTeam, err := s.teamRepo.FindByName(ctx, teamName)
-	if err != nil && !errors.Is(err, pg.ErrTeamNotFound) {
+	if err != nil && !errors.Is(err, repositories.ErrTeamNotFound) {
 		return fmt.Errorf("check for existing team: %w", err)
 	}
 	if existingTeam != nil {
+		// Replacing a roster is only allowed as a version-checked update.
+		if !hasExpectedVersion(ctx) {
+			return ErrTeamAlreadyExists
+		}
+		if err := checkExpectedVersion(ctx, existingTeam.Version); err != nil {
+			return err
+		}
 		team := &models.Team{
 			ID:      existingTeam.ID,
 			Name:    teamName,
+			Version: existingTeam.Version,
 			UserIDs: []uuid.UUID{},
 		}
 		for _, member := range members {
-			user, err := s.userRepo.FindByID(ctx, member.UserID)
-			if errors.Is(err, pg.ErrUserNotFound) {
+			user, err := s.userRepo.FindByExternalID(ctx, member.UserID)
+			if errors.Is(err, repositories.ErrUserNotFound) {
 				newUser := &models.User{
-					ID:       member.UserID,
-					Username: member.Username,
-					IsActive: member.IsActive,
+					ExternalID: member.UserID,
+					Username:   member.Username,
+					IsActive:   member.IsActive,
 				}
 				if err := s.userRepo.Create(ctx, newUser); err != nil {
 					return fmt.Errorf("create user %s: %w", member.UserID, err)
@@ -288,12 +668,12 @@ func (s *DefaultPullRequestService) CreateTeam(ctx context.Context, teamName str
 	}
 
 	for _, member := range members {
-		user, err := s.userRepo.FindByID(ctx, member.UserID)
-		if errors.Is(err, pg.ErrUserNotFound) {
+		user, err := s.userRepo.FindByExternalID(ctx, member.UserID)
+		if errors.Is(err, repositories.ErrUserNotFound) {
 			newUser := &models.User{
-				ID:       member.UserID,
-				Username: member.Username,
-				IsActive: member.IsActive,
+				ExternalID: member.UserID,
+				Username:   member.Username,
+				IsActive:   member.IsActive,
 			}
 			if err := s.userRepo.Create(ctx, newUser); err != nil {
 				return fmt.Errorf("create user %s: %w", member.UserID, err)
@@ -311,7 +691,11 @@ func (s *DefaultPullRequestService) CreateTeam(ctx context.Context, teamName str
 		}
 	}
 
-	if err := s.teamRepo.Create(ctx, team); err != nil {
+	err = s.teamRepo.Create(ctx, team)
+	if errors.Is(err, repositories.ErrDuplicateKey) {
+		return ErrTeamAlreadyExists
+	}
+	if err != nil {
 		return fmt.Errorf("create team: %w", err)
 	}
 
@@ -320,7 +704,7 @@ func (s *DefaultPullRequestService) CreateTeam(ctx context.Context, teamName str
 
 func (s *DefaultPullRequestService) GetTeam(ctx context.Context, teamName string) (*dtos.TeamDTO, error) {
 	team, err := s.teamRepo.FindByName(ctx, teamName)
-	if errors.Is(err, pg.ErrTeamNotFound) {
+	if errors.Is(err, repositories.ErrTeamNotFound) {
 		return nil, ErrTeamNotFound
 	}
 	if err != nil {
@@ -330,9 +714,9 @@ func (s *DefaultPullRequestService) GetTeam(ctx context.Context, teamName string
 	membersDTO := make([]dtos.TeamMemberDTO, len(team.UserIDs))
 	for i, userID := range team.UserIDs {
 		user, err := s.userRepo.FindByID(ctx, userID)
-		if errors.Is(err, pg.ErrUserNotFound) {
+		if errors.Is(err, repositories.ErrUserNotFound) {
 			membersDTO[i] = dtos.TeamMemberDTO{
-				UserID:   userID,
+				UserID:   userID.String(),
 				Username: "",
 				IsActive: false,
 			}
@@ -342,7 +726,7 @@ func (s *DefaultPullRequestService) GetTeam(ctx context.Context, teamName string
 			return nil, fmt.Errorf("find user %s for team %s: %w", userID, team.ID, err)
 		}
 		membersDTO[i] = dtos.TeamMemberDTO{
-			UserID:   user.ID,
+			UserID:   user.ExternalID,
 			Username: user.Username,
 			IsActive: user.IsActive,
 		}
@@ -351,12 +735,48 @@ func (s *DefaultPullRequestService) GetTeam(ctx context.Context, teamName string
 	return &dtos.TeamDTO{
 		TeamName: teamName,
 		Members:  membersDTO,
+		Version:  team.Version,
 	}, nil
 }
 
-func (s *DefaultPullRequestService) SetUserActive(ctx context.Context, userID uuid.UUID, isActive bool) (*dtos.UserDTO, error) {
-	user, err := s.userRepo.FindByID(ctx, userID)
-	if errors.Is(err, pg.ErrUserNotFound) {
+// deactivationReason is recorded on reassignments made by SetUserActive.
+const deactivationReason = "reviewer deactivated"
+
+// SetUserActive updates the user's flag. When a user is deactivated with
+// reassignOpenReviews, they are also replaced on every OPEN or DRAFT PR they
+// review; on PRs without a candidate they are unassigned, and those PRs are
+// listed in the report. Everything runs in one transaction.
+func (s *DefaultPullRequestService) SetUserActive(
+	ctx context.Context,
+	userID string,
+	isActive bool,
+	reassignOpenReviews bool,
+) (*dtos.UserSetActiveResponseDTO, error) {
+	// Users carry no version. Any expected version in ctx is dropped so that it
+	// is not checked against the PRs being reassigned.
+	ctx = withoutExpectedVersion(ctx)
+
+	return retryOnConflict(ctx, func() (*dtos.UserSetActiveResponseDTO, error) {
+		var resp *dtos.UserSetActiveResponseDTO
+		err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			user, err := s.setUserActive(ctx, userID, isActive)
+			if err != nil {
+				return err
+			}
+			resp = &dtos.UserSetActiveResponseDTO{User: *user}
+
+			if !isActive && reassignOpenReviews {
+				resp.Reassignment, err = s.reassignOpenReviews(ctx, userID)
+			}
+			return err
+		})
+		return resp, err
+	})
+}
+
+func (s *DefaultPullRequestService) setUserActive(ctx context.Context, userID string, isActive bool) (*dtos.UserDTO, error) {
+	user, err := s.userRepo.FindByExternalID(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
 		return nil, ErrUserNotFound
 	}
 	if err != nil {
@@ -368,9 +788,9 @@ func (s *DefaultPullRequestService) SetUserActive(ctx context.Context, userID uu
 		return nil, fmt.Errorf("update user: %w", err)
 	}
 
-	team, err := s.teamRepo.FindByUserID(ctx, userID)
+	team, err := s.teamRepo.FindByUserID(ctx, user.ID)
 	var teamName string
-	if errors.Is(err, pg.ErrTeamNotFound) {
+	if errors.Is(err, repositories.ErrTeamNotFound) {
 		teamName = ""
 	} else if err != nil {
 		return nil, fmt.Errorf("find team for user: %w", err)
@@ -379,50 +799,126 @@ func (s *DefaultPullRequestService) SetUserActive(ctx context.Context, userID uu
 	}
 
 	return &dtos.UserDTO{
-		UserID:   user.ID,
+		UserID:   user.ExternalID,
 		Username: user.Username,
 		TeamName: teamName,
 		IsActive: user.IsActive,
 	}, nil
 }
 
-func (s *DefaultPullRequestService) GetUserReviews(ctx context.Context, userID uuid.UUID) (*dtos.UserGetReviewResponseDTO, error) {
-	allPRs, err := s.prRepo.FindAll(ctx)
+func (s *DefaultPullRequestService) reassignOpenReviews(ctx context.Context, userID string) (*dtos.ReassignmentReportDTO, error) {
+	user, err := s.userRepo.FindByExternalID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("find all PRs to filter by reviewer: %w", err)
+		return nil, fmt.Errorf("find user %s: %w", userID, err)
 	}
 
-	var relevantPRs []dtos.PullRequestShortDTO
-	statusMap := make(map[uuid.UUID]string)
-
-	for _, pr := range allPRs {
-		isReviewer := false
-		for _, rid := range pr.ReviewersIDs {
-			if rid == userID {
-				isReviewer = true
-				break
+	prs, err := s.prRepo.FindByReviewer(ctx, user.ID, repositories.PullRequestFilter{
+		Statuses: []models.StatusName{models.StatusOpen, models.StatusDraft},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find open PRs reviewed by %s: %w", userID, err)
+	}
+
+	report := &dtos.ReassignmentReportDTO{
+		Reassigned:    []dtos.ReassignedReviewDTO{},
+		NotReassigned: []dtos.NotReassignedReviewDTO{},
+	}
+	for _, pr := range prs {
+		resp, err := s.reassignReviewer(ctx, userID, pr.ExternalID, deactivationReason)
+		switch {
+		case err == nil:
+			report.Reassigned = append(report.Reassigned, dtos.ReassignedReviewDTO{
+				PullRequestID: pr.ExternalID,
+				ReplacedBy:    resp.ReplacedBy,
+			})
+		case errors.Is(err, ErrNoReviewCandidates), errors.Is(err, ErrTeamNotFound):
+			if err := s.unassignReviewer(ctx, user, pr.ExternalID, deactivationReason); err != nil {
+				return nil, fmt.Errorf("unassign %s from PR %s: %w", userID, pr.ExternalID, err)
 			}
+			report.NotReassigned = append(report.NotReassigned, dtos.NotReassignedReviewDTO{
+				PullRequestID: pr.ExternalID,
+				Reason:        err.Error(),
+			})
+		default:
+			return nil, fmt.Errorf("reassign %s on PR %s: %w", userID, pr.ExternalID, err)
 		}
-		if !isReviewer {
-			continue
-		}
+	}
+
+	return report, nil
+}
+
+// unassignReviewer removes the reviewer from the PR without a replacement.
+func (s *DefaultPullRequestService) unassignReviewer(ctx context.Context, reviewer *models.User, prID string, reason string) error {
+	pr, err := s.prRepo.FindByExternalID(ctx, prID)
+	if err != nil {
+		return fmt.Errorf("find PR: %w", err)
+	}
+
+	index := slices.Index(pr.ReviewersIDs, reviewer.ID)
+	if index < 0 {
+		return ErrUserNotReviewer
+	}
+	pr.ReviewersIDs = slices.Delete(pr.ReviewersIDs, index, index+1)
+	delete(pr.Reviews, reviewer.ID)
+	pr.RecordEvent(newAssignmentEvent(ctx, models.EventUnassign, nil, &reviewer.ID, reason))
+
+	changed := dtos.ReviewersChangedEventDTO{
+		PullRequestID: prID,
+		Added:         []string{},
+		Removed:       []string{reviewer.ExternalID},
+	}
+	if err := addPendingEvent(pr, EventPullRequestReviewersChanged, changed); err != nil {
+		return err
+	}
+
+	if err := s.prRepo.Update(ctx, pr); err != nil {
+		return fmt.Errorf("update pull request after unassignment: %w", err)
+	}
+	return nil
+}
+
+func (s *DefaultPullRequestService) GetUserReviews(ctx context.Context, userID string) (*dtos.UserGetReviewResponseDTO, error) {
+	user, err := s.userRepo.FindByExternalID(ctx, userID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return &dtos.UserGetReviewResponseDTO{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find user %s: %w", userID, err)
+	}
+
+	reviewPRs, err := s.prRepo.FindByReviewer(ctx, user.ID, repositories.PullRequestFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("find PRs reviewed by %s: %w", userID, err)
+	}
+
+	statuses, err := s.statusRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get statuses for review list: %w", err)
+	}
+	statusMap := make(map[uuid.UUID]string, len(statuses))
+	for _, st := range statuses {
+		statusMap[st.ID] = st.Name
+	}
+
+	var relevantPRs []dtos.PullRequestShortDTO
+	authorMap := make(map[uuid.UUID]string)
+
+	for _, pr := range reviewPRs {
+		statusName := statusMap[pr.StatusID]
 
-		statusName, ok := statusMap[pr.StatusID]
+		authorID, ok := authorMap[pr.AuthorID]
 		if !ok {
-			status, err := s.statusRepo.FindByID(ctx, pr.StatusID)
-			if err != nil && !errors.Is(err, pg.ErrStatusNotFound) {
-				return nil, fmt.Errorf("find status for PR %s: %w", pr.ID, err)
-			}
-			if status != nil {
-				statusName = status.Name
+			authorID, err = s.externalUserID(ctx, pr.AuthorID)
+			if err != nil {
+				return nil, err
 			}
-			statusMap[pr.StatusID] = statusName
+			authorMap[pr.AuthorID] = authorID
 		}
 
 		relevantPRs = append(relevantPRs, dtos.PullRequestShortDTO{
-			PullRequestID:   pr.ID,
+			PullRequestID:   pr.ExternalID,
 			PullRequestName: pr.Title,
-			AuthorID:        pr.AuthorID,
+			AuthorID:        authorID,
 			Status:          statusName,
 		})
 	}
@@ -433,18 +929,97 @@ func (s *DefaultPullRequestService) GetUserReviews(ctx context.Context, userID u
 	}, nil
 }
 
-func chooseRandomUsers(userIDs []uuid.UUID, max int) []uuid.UUID {
-	n := len(userIDs)
-	if n <= max {
-		return append([]uuid.UUID{}, userIDs...)
+func (s *DefaultPullRequestService) GetTeamSettings(ctx context.Context, teamName string) (*dtos.TeamSettingsDTO, error) {
+	team, err := s.teamRepo.FindByName(ctx, teamName)
+	if errors.Is(err, repositories.ErrTeamNotFound) {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find team by name: %w", err)
+	}
+
+	settings, err := s.teamSettings(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTeamSettingsToDTO(team.Name, settings), nil
+}
+
+func (s *DefaultPullRequestService) UpdateTeamSettings(ctx context.Context, req dtos.TeamSettingsDTO) (*dtos.TeamSettingsDTO, error) {
+	if req.ReviewerCount < 1 {
+		return nil, fmt.Errorf("%w: reviewer_count must be at least 1", ErrInvalidTeamSettings)
+	}
+	if req.MinReviewers < 0 || req.MinReviewers > req.ReviewerCount {
+		return nil, fmt.Errorf("%w: min_reviewers must be between 0 and reviewer_count", ErrInvalidTeamSettings)
+	}
+	if req.RequiredApprovals < 0 || req.RequiredApprovals > req.ReviewerCount {
+		return nil, fmt.Errorf("%w: required_approvals must be between 0 and reviewer_count", ErrInvalidTeamSettings)
+	}
+	if req.SelectionStrategy != "" && !isKnownStrategy(req.SelectionStrategy) {
+		return nil, fmt.Errorf("%w: unknown selection_strategy %q", ErrInvalidTeamSettings, req.SelectionStrategy)
+	}
+
+	team, err := s.teamRepo.FindByName(ctx, req.TeamName)
+	if errors.Is(err, repositories.ErrTeamNotFound) {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find team by name: %w", err)
+	}
+
+	settings := &models.TeamSettings{
+		TeamID:            team.ID,
+		ReviewerCount:     req.ReviewerCount,
+		MinReviewers:      req.MinReviewers,
+		RequiredApprovals: req.RequiredApprovals,
+		SelectionStrategy: req.SelectionStrategy,
+	}
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		return nil, fmt.Errorf("save team settings: %w", err)
+	}
+
+	return convertTeamSettingsToDTO(team.Name, settings), nil
+}
+
+// withoutUnavailable drops users who are inside an unavailability period right
+// now, keeping the order of the rest.
+func (s *DefaultPullRequestService) withoutUnavailable(ctx context.Context, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+	unavailable, err := s.unavailable.FindUnavailableUserIDs(ctx, userIDs, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("find unavailable users: %w", err)
+	}
+	return slices.DeleteFunc(userIDs, func(id uuid.UUID) bool { return unavailable[id] }), nil
+}
+
+func (s *DefaultPullRequestService) teamSettings(ctx context.Context, teamID uuid.UUID) (*models.TeamSettings, error) {
+	settings, err := s.settingsRepo.FindByTeamID(ctx, teamID)
+	if errors.Is(err, repositories.ErrTeamSettingsNotFound) {
+		return models.DefaultTeamSettings(teamID), nil
 	}
+	if err != nil {
+		return nil, fmt.Errorf("find settings for team %s: %w", teamID, err)
+	}
+	return settings, nil
+}
 
-	selected := make([]uuid.UUID, 0, max)
-	perm := rand.Perm(n)
-	for i := 0; i < max; i++ {
-		selected = append(selected, userIDs[perm[i]])
+func newAssignmentEvent(
+	ctx context.Context,
+	eventType models.AssignmentEventType,
+	reviewerID *uuid.UUID,
+	previousReviewerID *uuid.UUID,
+	reason string,
+) models.AssignmentEvent {
+	return models.AssignmentEvent{
+		Type:               eventType,
+		ReviewerID:         reviewerID,
+		PreviousReviewerID: previousReviewerID,
+		Actor:              ActorFromContext(ctx),
+		Reason:             reason,
 	}
-	return selected
 }
 
 func contains(slice []uuid.UUID, item uuid.UUID) bool {
@@ -456,6 +1031,15 @@ func contains(slice []uuid.UUID, item uuid.UUID) bool {
 	return false
 }
 
+func findStatusID(statuses []*models.Status, name models.StatusName) (uuid.UUID, error) {
+	for _, st := range statuses {
+		if st.StatusName() == name {
+			return st.ID, nil
+		}
+	}
+	return uuid.Nil, fmt.Errorf("status '%s' not found in database", name)
+}
+
 func convertStatusToStringMap(statuses []*models.Status) map[uuid.UUID]string {
 	m := make(map[uuid.UUID]string, len(statuses))
 	for _, st := range statuses {
@@ -464,17 +1048,86 @@ func convertStatusToStringMap(statuses []*models.Status) map[uuid.UUID]string {
 	return m
 }
 
-func convertPullRequestToDTO(pr *models.PullRequest, statuses []*models.Status) *dtos.PullRequestDTO {
-	statusMap := convertStatusToStringMap(statuses)
+func (s *DefaultPullRequestService) convertPullRequestToDTO(ctx context.Context, pr *models.PullRequest, statuses []*models.Status) (*dtos.PullRequestDTO, error) {
+	return s.pullRequestDTO(ctx, pr, convertStatusToStringMap(statuses), make(map[uuid.UUID]string))
+}
+
+// pullRequestDTO converts pr using externalIDs as a cache of user ID
+// lookups, so converting a page of PRs looks up each user once.
+func (s *DefaultPullRequestService) pullRequestDTO(
+	ctx context.Context,
+	pr *models.PullRequest,
+	statusMap map[uuid.UUID]string,
+	externalIDs map[uuid.UUID]string,
+) (*dtos.PullRequestDTO, error) {
+	lookup := func(id uuid.UUID) (string, error) {
+		if ext, ok := externalIDs[id]; ok {
+			return ext, nil
+		}
+		ext, err := s.externalUserID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		externalIDs[id] = ext
+		return ext, nil
+	}
+
 	statusName := statusMap[pr.StatusID]
 
+	authorID, err := lookup(pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]string, 0, len(pr.ReviewersIDs))
+	reviews := make([]dtos.ReviewDTO, 0, len(pr.ReviewersIDs))
+	for _, rid := range pr.ReviewersIDs {
+		reviewerID, err := lookup(rid)
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, reviewerID)
+
+		review := pr.Review(rid)
+		reviews = append(reviews, dtos.ReviewDTO{
+			ReviewerID: reviewerID,
+			Verdict:    string(review.Verdict),
+			VerdictAt:  review.VerdictAt,
+		})
+	}
+
 	return &dtos.PullRequestDTO{
-		PullRequestID:     pr.ID,
+		PullRequestID:     pr.ExternalID,
 		PullRequestName:   pr.Title,
-		AuthorID:          pr.AuthorID,
+		AuthorID:          authorID,
 		Status:            statusName,
-		AssignedReviewers: pr.ReviewersIDs,
+		AssignedReviewers: reviewers,
+		Reviews:           reviews,
 		CreatedAt:         &pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
+		Version:           pr.Version,
+	}, nil
+}
+
+// externalUserID maps an internal user UUID to the identifier clients know the
+// user by. Dangling references fall back to the UUID itself.
+func (s *DefaultPullRequestService) externalUserID(ctx context.Context, id uuid.UUID) (string, error) {
+	u, err := s.userRepo.FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return id.String(), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find user %s: %w", id, err)
+	}
+	return u.ExternalID, nil
+}
+
+func convertTeamSettingsToDTO(teamName string, settings *models.TeamSettings) *dtos.TeamSettingsDTO {
+	return &dtos.TeamSettingsDTO{
+		TeamName:          teamName,
+		ReviewerCount:     settings.ReviewerCount,
+		MinReviewers:      settings.MinReviewers,
+		RequiredApprovals: settings.RequiredApprovals,
+		SelectionStrategy: settings.SelectionStrategy,
 	}
 }
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// listCursor is the JSON behind the opaque next_cursor value.
+type listCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (s *DefaultPullRequestService) ListPullRequests(ctx context.Context, query dtos.PullRequestListQueryDTO) (*dtos.PullRequestListResponseDTO, error) {
+	filter, err := parseListQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unknown users or teams cannot match anything, so they yield an empty
+	// page rather than an error, like /users/getReview.
+	empty := &dtos.PullRequestListResponseDTO{PullRequests: []dtos.PullRequestDTO{}}
+
+	if query.AuthorID != "" {
+		author, err := s.userRepo.FindByExternalID(ctx, query.AuthorID)
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return empty, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find author %s: %w", query.AuthorID, err)
+		}
+		filter.AuthorIDs = []uuid.UUID{author.ID}
+	}
+
+	if query.TeamName != "" {
+		team, err := s.teamRepo.FindByName(ctx, query.TeamName)
+		if errors.Is(err, repositories.ErrTeamNotFound) {
+			return empty, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find team %s: %w", query.TeamName, err)
+		}
+		if filter.AuthorIDs != nil {
+			filter.AuthorIDs = slices.DeleteFunc(filter.AuthorIDs, func(id uuid.UUID) bool {
+				return !slices.Contains(team.UserIDs, id)
+			})
+		} else {
+			filter.AuthorIDs = slices.Clone(team.UserIDs)
+		}
+		if len(filter.AuthorIDs) == 0 {
+			return empty, nil
+		}
+	}
+
+	if query.ReviewerID != "" {
+		reviewer, err := s.userRepo.FindByExternalID(ctx, query.ReviewerID)
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return empty, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find reviewer %s: %w", query.ReviewerID, err)
+		}
+		filter.ReviewerID = &reviewer.ID
+	}
+
+	// One extra row tells whether another page exists.
+	limit := filter.Limit
+	filter.Limit++
+
+	prs, err := s.prRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list pull requests: %w", err)
+	}
+
+	resp := &dtos.PullRequestListResponseDTO{PullRequests: make([]dtos.PullRequestDTO, 0, min(len(prs), limit))}
+	if len(prs) > limit {
+		prs = prs[:limit]
+		last := prs[len(prs)-1]
+		resp.NextCursor = encodeListCursor(listCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	statuses, err := s.statusRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get statuses for list: %w", err)
+	}
+	statusMap := convertStatusToStringMap(statuses)
+	externalIDs := make(map[uuid.UUID]string)
+
+	for _, pr := range prs {
+		dto, err := s.pullRequestDTO(ctx, pr, statusMap, externalIDs)
+		if err != nil {
+			return nil, err
+		}
+		resp.PullRequests = append(resp.PullRequests, *dto)
+	}
+
+	return resp, nil
+}
+
+func parseListQuery(query dtos.PullRequestListQueryDTO) (repositories.PullRequestFilter, error) {
+	filter := repositories.PullRequestFilter{Limit: defaultListLimit}
+
+	for _, name := range query.Statuses {
+		status := models.StatusName(name)
+		if !slices.Contains(models.StatusNames, status) {
+			return filter, fmt.Errorf("%w: unknown status %q", ErrInvalidListQuery, name)
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	ranges := []struct {
+		param string
+		value string
+		dst   **time.Time
+	}{
+		{"created_from", query.CreatedFrom, &filter.CreatedFrom},
+		{"created_to", query.CreatedTo, &filter.CreatedTo},
+		{"merged_from", query.MergedFrom, &filter.MergedFrom},
+		{"merged_to", query.MergedTo, &filter.MergedTo},
+	}
+	for _, r := range ranges {
+		if r.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, r.value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", ErrInvalidListQuery, r.param)
+		}
+		*r.dst = &t
+	}
+
+	if query.Limit != "" {
+		limit, err := strconv.Atoi(query.Limit)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return filter, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListQuery, maxListLimit)
+		}
+		filter.Limit = limit
+	}
+
+	if query.Cursor != "" {
+		cursor, err := decodeListCursor(query.Cursor)
+		if err != nil {
+			return filter, fmt.Errorf("%w: malformed cursor", ErrInvalidListQuery)
+		}
+		filter.After = &repositories.PullRequestCursor{CreatedAt: cursor.CreatedAt, ID: cursor.ID}
+	}
+
+	return filter, nil
+}
+
+func encodeListCursor(c listCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeListCursor(s string) (listCursor, error) {
+	var c listCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, err
+	}
+	if c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return c, errors.New("incomplete cursor")
+	}
+	return c, nil
+}
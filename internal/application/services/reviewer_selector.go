@@ -0,0 +1,293 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StrategyRandom         = "random"
+	StrategyRoundRobin     = "round_robin"
+	StrategyWeightedRandom = "weighted_random"
+	StrategyLeastLoaded    = "least_loaded"
+)
+
+var Strategies = []string{StrategyRandom, StrategyRoundRobin, StrategyWeightedRandom, StrategyLeastLoaded}
+
+// ReviewerSelector picks up to count reviewers out of candidates, which are
+// already filtered to active team members other than the author.
+type ReviewerSelector interface {
+	Select(ctx context.Context, team *models.Team, candidates []uuid.UUID, count int) ([]uuid.UUID, error)
+}
+
+// TeamSelector dispatches per team: the strategy stored in the team's settings
+// wins, then the deployment's per-team override by team name, then fallback.
+type TeamSelector struct {
+	fallback   ReviewerSelector
+	byStrategy map[string]ReviewerSelector
+	byTeam     map[string]ReviewerSelector
+	settings   repositories.TeamSettings
+}
+
+func NewTeamSelector(
+	fallback ReviewerSelector,
+	byStrategy map[string]ReviewerSelector,
+	byTeam map[string]ReviewerSelector,
+	settings repositories.TeamSettings,
+) *TeamSelector {
+	return &TeamSelector{
+		fallback:   fallback,
+		byStrategy: byStrategy,
+		byTeam:     byTeam,
+		settings:   settings,
+	}
+}
+
+func (s *TeamSelector) Select(ctx context.Context, team *models.Team, candidates []uuid.UUID, count int) ([]uuid.UUID, error) {
+	sel, err := s.resolve(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	return sel.Select(ctx, team, candidates, count)
+}
+
+func (s *TeamSelector) resolve(ctx context.Context, team *models.Team) (ReviewerSelector, error) {
+	settings, err := s.settings.FindByTeamID(ctx, team.ID)
+	if err != nil && !errors.Is(err, repositories.ErrTeamSettingsNotFound) {
+		return nil, fmt.Errorf("find settings for team %s: %w", team.ID, err)
+	}
+	if settings != nil && settings.SelectionStrategy != "" {
+		sel, ok := s.byStrategy[settings.SelectionStrategy]
+		if !ok {
+			return nil, fmt.Errorf("team %s: strategy %q is not configured", team.ID, settings.SelectionStrategy)
+		}
+		return sel, nil
+	}
+
+	if sel, ok := s.byTeam[team.Name]; ok {
+		return sel, nil
+	}
+	return s.fallback, nil
+}
+
+type RandomSelector struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func NewRandomSelector(rnd *rand.Rand) *RandomSelector {
+	return &RandomSelector{rnd: orNewRand(rnd)}
+}
+
+func (s *RandomSelector) Select(ctx context.Context, team *models.Team, candidates []uuid.UUID, count int) ([]uuid.UUID, error) {
+	if len(candidates) <= count {
+		return append([]uuid.UUID{}, candidates...), nil
+	}
+
+	s.mu.Lock()
+	perm := s.rnd.Perm(len(candidates))
+	s.mu.Unlock()
+
+	selected := make([]uuid.UUID, 0, count)
+	for _, i := range perm[:count] {
+		selected = append(selected, candidates[i])
+	}
+	return selected, nil
+}
+
+// RoundRobinSelector walks each team's candidates in a stable order, resuming
+// after the last reviewer it handed out for that team.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[uuid.UUID]uuid.UUID
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{last: make(map[uuid.UUID]uuid.UUID)}
+}
+
+func (s *RoundRobinSelector) Select(ctx context.Context, team *models.Team, candidates []uuid.UUID, count int) ([]uuid.UUID, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	ordered := append([]uuid.UUID{}, candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return bytes.Compare(ordered[i][:], ordered[j][:]) < 0
+	})
+	if count > len(ordered) {
+		count = len(ordered)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := 0
+	if last, ok := s.last[team.ID]; ok {
+		start = sort.Search(len(ordered), func(i int) bool {
+			return bytes.Compare(ordered[i][:], last[:]) > 0
+		})
+	}
+
+	selected := make([]uuid.UUID, 0, count)
+	for i := 0; i < count; i++ {
+		selected = append(selected, ordered[(start+i)%len(ordered)])
+	}
+	s.last[team.ID] = selected[len(selected)-1]
+
+	return selected, nil
+}
+
+// WeightedRandomSelector draws reviewers without replacement with probability
+// proportional to their weight. Users without an explicit weight get 1; users
+// with a weight of zero or less are never picked.
+type WeightedRandomSelector struct {
+	mu      sync.Mutex
+	rnd     *rand.Rand
+	weights map[uuid.UUID]float64
+}
+
+func NewWeightedRandomSelector(rnd *rand.Rand, weights map[uuid.UUID]float64) *WeightedRandomSelector {
+	return &WeightedRandomSelector{rnd: orNewRand(rnd), weights: weights}
+}
+
+func (s *WeightedRandomSelector) Select(ctx context.Context, team *models.Team, candidates []uuid.UUID, count int) ([]uuid.UUID, error) {
+	pool := make([]uuid.UUID, 0, len(candidates))
+	weights := make([]float64, 0, len(candidates))
+	for _, id := range candidates {
+		w := s.weight(id)
+		if w <= 0 {
+			continue
+		}
+		pool = append(pool, id)
+		weights = append(weights, w)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	selected := make([]uuid.UUID, 0, count)
+	for len(selected) < count && len(pool) > 0 {
+		total := 0.0
+		for _, w := range weights {
+			total += w
+		}
+
+		target := s.rnd.Float64() * total
+		idx := len(pool) - 1
+		for i, w := range weights {
+			if target < w {
+				idx = i
+				break
+			}
+			target -= w
+		}
+
+		selected = append(selected, pool[idx])
+		pool = append(pool[:idx], pool[idx+1:]...)
+		weights = append(weights[:idx], weights[idx+1:]...)
+	}
+
+	return selected, nil
+}
+
+func (s *WeightedRandomSelector) weight(id uuid.UUID) float64 {
+	if w, ok := s.weights[id]; ok {
+		return w
+	}
+	return 1
+}
+
+// ReviewLoadCounter reports how many OPEN pull requests each reviewer is
+// currently assigned to. Reviewers without open reviews may be omitted.
+type ReviewLoadCounter interface {
+	CountOpenReviews(ctx context.Context, reviewerIDs []uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+// LeastLoadedSelector prefers the candidates with the fewest open reviews and
+// breaks ties randomly.
+type LeastLoadedSelector struct {
+	counter ReviewLoadCounter
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func NewLeastLoadedSelector(counter ReviewLoadCounter, rnd *rand.Rand) *LeastLoadedSelector {
+	return &LeastLoadedSelector{counter: counter, rnd: orNewRand(rnd)}
+}
+
+func (s *LeastLoadedSelector) Select(ctx context.Context, team *models.Team, candidates []uuid.UUID, count int) ([]uuid.UUID, error) {
+	loads, err := s.counter.CountOpenReviews(ctx, candidates)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews: %w", err)
+	}
+
+	ordered := append([]uuid.UUID{}, candidates...)
+	s.mu.Lock()
+	s.rnd.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	s.mu.Unlock()
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return loads[ordered[i]] < loads[ordered[j]]
+	})
+
+	if count > len(ordered) {
+		count = len(ordered)
+	}
+	return ordered[:count], nil
+}
+
+type SelectorOptions struct {
+	// Weights is used by the weighted_random strategy.
+	Weights map[uuid.UUID]float64
+	// LoadCounter is required by the least_loaded strategy.
+	LoadCounter ReviewLoadCounter
+}
+
+// NewReviewerSelector builds a built-in selector by strategy name.
+func NewReviewerSelector(strategy string, opts SelectorOptions) (ReviewerSelector, error) {
+	switch strategy {
+	case StrategyRandom:
+		return NewRandomSelector(nil), nil
+	case StrategyRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case StrategyWeightedRandom:
+		return NewWeightedRandomSelector(nil, opts.Weights), nil
+	case StrategyLeastLoaded:
+		if opts.LoadCounter == nil {
+			return nil, fmt.Errorf("strategy %q requires a load counter", strategy)
+		}
+		return NewLeastLoadedSelector(opts.LoadCounter, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer selection strategy %q", strategy)
+	}
+}
+
+func orNewRand(rnd *rand.Rand) *rand.Rand {
+	if rnd != nil {
+		return rnd
+	}
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+func isKnownStrategy(strategy string) bool {
+	for _, s := range Strategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
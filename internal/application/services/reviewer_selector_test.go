@@ -0,0 +1,231 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/database/memory"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// draws is how many selections the distribution tests make. With a fixed
+// seed the results are deterministic; the bounds only document the intent.
+const draws = 10000
+
+func sortedIDs(n int) []uuid.UUID {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	return ids
+}
+
+func seeded() *rand.Rand {
+	return rand.New(rand.NewSource(42))
+}
+
+func mustSelect(t *testing.T, sel services.ReviewerSelector, team *models.Team, candidates []uuid.UUID, count int) []uuid.UUID {
+	t.Helper()
+
+	got, err := sel.Select(context.Background(), team, candidates, count)
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	return got
+}
+
+// checkSelection fails unless got holds want distinct members of candidates.
+func checkSelection(t *testing.T, got, candidates []uuid.UUID, want int) {
+	t.Helper()
+
+	if len(got) != want {
+		t.Fatalf("selected %d reviewers, want %d", len(got), want)
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range got {
+		if !slices.Contains(candidates, id) || seen[id] {
+			t.Fatalf("selection %v is not %d distinct candidates", got, want)
+		}
+		seen[id] = true
+	}
+}
+
+func TestRandomSelector(t *testing.T) {
+	team := &models.Team{ID: uuid.New()}
+	candidates := sortedIDs(4)
+
+	sel := services.NewRandomSelector(seeded())
+	counts := make(map[uuid.UUID]int)
+	for i := 0; i < draws; i++ {
+		got := mustSelect(t, sel, team, candidates, 2)
+		checkSelection(t, got, candidates, 2)
+		for _, id := range got {
+			counts[id]++
+		}
+	}
+	for _, id := range candidates {
+		if n := counts[id]; n < draws/2-draws/10 || n > draws/2+draws/10 {
+			t.Errorf("candidate picked %d times out of %d, want about half", n, draws)
+		}
+	}
+
+	// The same seed gives the same picks.
+	a := mustSelect(t, services.NewRandomSelector(seeded()), team, candidates, 2)
+	b := mustSelect(t, services.NewRandomSelector(seeded()), team, candidates, 2)
+	if !slices.Equal(a, b) {
+		t.Errorf("seeded selections differ: %v, %v", a, b)
+	}
+
+	if got := mustSelect(t, sel, team, candidates[:1], 2); !slices.Equal(got, candidates[:1]) {
+		t.Errorf("Select with fewer candidates than count = %v, want %v", got, candidates[:1])
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	teamA := &models.Team{ID: uuid.New()}
+	teamB := &models.Team{ID: uuid.New()}
+	ids := sortedIDs(3)
+	shuffled := []uuid.UUID{ids[2], ids[0], ids[1]}
+
+	sel := services.NewRoundRobinSelector()
+	steps := []struct {
+		team       *models.Team
+		candidates []uuid.UUID
+		count      int
+		want       []uuid.UUID
+	}{
+		{teamA, shuffled, 2, []uuid.UUID{ids[0], ids[1]}},
+		{teamA, shuffled, 2, []uuid.UUID{ids[2], ids[0]}},
+		// Teams rotate independently.
+		{teamB, shuffled, 1, []uuid.UUID{ids[0]}},
+		// A missing last reviewer resumes after its position.
+		{teamA, []uuid.UUID{ids[1], ids[2]}, 1, []uuid.UUID{ids[1]}},
+		{teamA, shuffled, 5, []uuid.UUID{ids[2], ids[0], ids[1]}},
+		{teamA, nil, 2, []uuid.UUID{}},
+	}
+	for i, step := range steps {
+		if got := mustSelect(t, sel, step.team, step.candidates, step.count); !slices.Equal(got, step.want) {
+			t.Errorf("step %d: Select = %v, want %v", i, got, step.want)
+		}
+	}
+}
+
+func TestWeightedRandomSelector(t *testing.T) {
+	team := &models.Team{ID: uuid.New()}
+	ids := sortedIDs(3)
+	heavy, light, excluded := ids[0], ids[1], ids[2]
+
+	sel := services.NewWeightedRandomSelector(seeded(), map[uuid.UUID]float64{heavy: 9, excluded: 0})
+	heavyFirst := 0
+	for i := 0; i < draws; i++ {
+		got := mustSelect(t, sel, team, ids, 1)
+		checkSelection(t, got, ids, 1)
+		switch got[0] {
+		case heavy:
+			heavyFirst++
+		case excluded:
+			t.Fatal("candidate with weight 0 was selected")
+		}
+	}
+	// heavy has weight 9 and light the default 1.
+	if heavyFirst < draws*85/100 || heavyFirst > draws*95/100 {
+		t.Errorf("weight 9 candidate picked %d times out of %d, want about 90%%", heavyFirst, draws)
+	}
+
+	got := mustSelect(t, sel, team, ids, 3)
+	checkSelection(t, got, []uuid.UUID{heavy, light}, 2)
+}
+
+type fakeLoadCounter struct {
+	loads map[uuid.UUID]int
+	err   error
+}
+
+func (c fakeLoadCounter) CountOpenReviews(ctx context.Context, reviewerIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	return c.loads, c.err
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	team := &models.Team{ID: uuid.New()}
+	ids := sortedIDs(4)
+	idle, tiedA, tiedB, busy := ids[0], ids[1], ids[2], ids[3]
+	counter := fakeLoadCounter{loads: map[uuid.UUID]int{tiedA: 2, tiedB: 2, busy: 5}}
+
+	sel := services.NewLeastLoadedSelector(counter, seeded())
+	seen := make(map[uuid.UUID]int)
+	for i := 0; i < draws/10; i++ {
+		got := mustSelect(t, sel, team, ids, 2)
+		if got[0] != idle || (got[1] != tiedA && got[1] != tiedB) {
+			t.Fatalf("Select = %v, want the idle reviewer and one of the tied ones", got)
+		}
+		seen[got[1]]++
+	}
+	if seen[tiedA] == 0 || seen[tiedB] == 0 {
+		t.Errorf("ties were not broken randomly: %v", seen)
+	}
+
+	if got := mustSelect(t, sel, team, ids, 10); len(got) != 4 || got[3] != busy {
+		t.Errorf("Select(10) = %v, want all four with the busiest last", got)
+	}
+
+	failing := services.NewLeastLoadedSelector(fakeLoadCounter{err: errors.New("db down")}, seeded())
+	if _, err := failing.Select(context.Background(), team, ids, 2); err == nil {
+		t.Error("Select succeeded although counting failed")
+	}
+}
+
+// fixedSelector returns its own ID so tests can tell selectors apart.
+type fixedSelector uuid.UUID
+
+func (s fixedSelector) Select(ctx context.Context, team *models.Team, candidates []uuid.UUID, count int) ([]uuid.UUID, error) {
+	return []uuid.UUID{uuid.UUID(s)}, nil
+}
+
+func TestTeamSelector(t *testing.T) {
+	ctx := context.Background()
+	fallback, random, roundRobin := fixedSelector(uuid.New()), fixedSelector(uuid.New()), fixedSelector(uuid.New())
+	settings := memory.NewTeamSettingsRepository()
+	sel := services.NewTeamSelector(
+		fallback,
+		map[string]services.ReviewerSelector{services.StrategyRandom: random},
+		map[string]services.ReviewerSelector{"backend": roundRobin},
+		settings,
+	)
+
+	configured := &models.Team{ID: uuid.New(), Name: "backend"}
+	if err := settings.Upsert(ctx, &models.TeamSettings{TeamID: configured.ID, SelectionStrategy: services.StrategyRandom}); err != nil {
+		t.Fatal(err)
+	}
+	misconfigured := &models.Team{ID: uuid.New(), Name: "frontend"}
+	if err := settings.Upsert(ctx, &models.TeamSettings{TeamID: misconfigured.ID, SelectionStrategy: services.StrategyLeastLoaded}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		team *models.Team
+		want fixedSelector
+	}{
+		{"team settings win", configured, random},
+		{"per-team override", &models.Team{ID: uuid.New(), Name: "backend"}, roundRobin},
+		{"fallback", &models.Team{ID: uuid.New(), Name: "mobile"}, fallback},
+	}
+	for _, tt := range tests {
+		got := mustSelect(t, sel, tt.team, nil, 1)
+		if got[0] != uuid.UUID(tt.want) {
+			t.Errorf("%s: used the wrong selector", tt.name)
+		}
+	}
+
+	if _, err := sel.Select(ctx, misconfigured, nil, 1); err == nil {
+		t.Error("Select succeeded with a strategy that is not configured")
+	}
+}
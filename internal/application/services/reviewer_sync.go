@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"time"
+)
+
+var (
+	// ErrCodeHostRejected marks provider errors that retrying cannot fix, such
+	// as a reviewer who is not a collaborator on the repository.
+	ErrCodeHostRejected = errors.New("code host rejected the request")
+)
+
+// PullRequestRef addresses a pull request on a code host.
+type PullRequestRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// CodeHostProvider mirrors reviewer assignments onto the pull request hosted
+// by a code host.
+type CodeHostProvider interface {
+	// Name is the provider key used for user identities.
+	Name() string
+	// ParsePullRequestID reports whether a pull request ID belongs to this
+	// provider and where it lives.
+	ParsePullRequestID(prID string) (PullRequestRef, bool)
+	RequestReviewers(ctx context.Context, ref PullRequestRef, logins []string) error
+	RemoveReviewers(ctx context.Context, ref PullRequestRef, logins []string) error
+}
+
+// ReviewerSyncOptions bounds the retries made within one Publish call. When
+// they run out, the outbox publishes the event again on a later poll.
+type ReviewerSyncOptions struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultReviewerSyncOptions() ReviewerSyncOptions {
+	return ReviewerSyncOptions{
+		MaxAttempts: 3,
+		BaseBackoff: time.Second,
+		MaxBackoff:  10 * time.Second,
+	}
+}
+
+// ReviewerSync implements EventPublisher for reviewers_changed events. Publish
+// applies the change to the code host before returning, so the outbox only
+// marks the event published once the code host has it.
+type ReviewerSync struct {
+	provider   CodeHostProvider
+	userRepo   repositories.User
+	identities repositories.UserIdentity
+	opts       ReviewerSyncOptions
+	backoff    *Backoff
+}
+
+func NewReviewerSync(
+	provider CodeHostProvider,
+	userRepo repositories.User,
+	identities repositories.UserIdentity,
+	opts ReviewerSyncOptions,
+) *ReviewerSync {
+	defaults := DefaultReviewerSyncOptions()
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaults.BaseBackoff
+	}
+	if opts.MaxBackoff < opts.BaseBackoff {
+		opts.MaxBackoff = max(defaults.MaxBackoff, opts.BaseBackoff)
+	}
+
+	return &ReviewerSync{
+		provider:   provider,
+		userRepo:   userRepo,
+		identities: identities,
+		opts:       opts,
+		backoff:    NewBackoff(opts.BaseBackoff, opts.MaxBackoff, nil),
+	}
+}
+
+// Publish ignores other event types and pull requests hosted elsewhere.
+// Changes the code host rejects for good are logged and dropped; any other
+// failure is returned so that the outbox redelivers the event.
+func (s *ReviewerSync) Publish(ctx context.Context, event Event) error {
+	if event.Type != EventPullRequestReviewersChanged {
+		return nil
+	}
+
+	var changed dtos.ReviewersChangedEventDTO
+	if err := json.Unmarshal(event.Data, &changed); err != nil {
+		return fmt.Errorf("decode %s event %s: %w", event.Type, event.ID, err)
+	}
+
+	ref, ok := s.provider.ParsePullRequestID(changed.PullRequestID)
+	if !ok {
+		return nil
+	}
+
+	add, err := s.logins(ctx, changed.Added)
+	if err != nil {
+		return err
+	}
+	remove, err := s.logins(ctx, changed.Removed)
+	if err != nil {
+		return err
+	}
+	if len(remove) > 0 {
+		if err := s.retry(ctx, func() error { return s.provider.RemoveReviewers(ctx, ref, remove) }); err != nil {
+			return s.failed(event, ref, "remove reviewers", err)
+		}
+	}
+	if len(add) > 0 {
+		if err := s.retry(ctx, func() error { return s.provider.RequestReviewers(ctx, ref, add) }); err != nil {
+			return s.failed(event, ref, "request reviewers", err)
+		}
+	}
+	return nil
+}
+
+// logins maps external user IDs to provider logins. A linked identity wins;
+// otherwise the external ID is assumed to be the login, mirroring ResolveLogin.
+func (s *ReviewerSync) logins(ctx context.Context, userIDs []string) ([]string, error) {
+	logins := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		user, err := s.userRepo.FindByExternalID(ctx, userID)
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find user %s: %w", userID, err)
+		}
+
+		identity, err := s.identities.FindByUserID(ctx, s.provider.Name(), user.ID)
+		switch {
+		case err == nil:
+			logins = append(logins, identity.Login)
+		case errors.Is(err, repositories.ErrUserIdentityNotFound):
+			logins = append(logins, user.ExternalID)
+		default:
+			return nil, fmt.Errorf("find %s identity of user %s: %w", s.provider.Name(), userID, err)
+		}
+	}
+	return logins, nil
+}
+
+// retry calls until it succeeds, the code host rejects the request or the
+// attempts run out.
+func (s *ReviewerSync) retry(ctx context.Context, call func() error) error {
+	var err error
+	for attempt := 1; attempt <= s.opts.MaxAttempts; attempt++ {
+		err = call()
+		if err == nil || errors.Is(err, ErrCodeHostRejected) || attempt == s.opts.MaxAttempts {
+			return err
+		}
+		if waitErr := s.backoff.Wait(ctx, attempt); waitErr != nil {
+			return fmt.Errorf("%w (interrupted before retry: %v)", err, waitErr)
+		}
+	}
+	return err
+}
+
+func (s *ReviewerSync) failed(event Event, ref PullRequestRef, op string, err error) error {
+	if errors.Is(err, ErrCodeHostRejected) {
+		log.Printf("%s reviewer sync: %s on %s/%s#%d (event %s) rejected: %v",
+			s.provider.Name(), op, ref.Owner, ref.Repo, ref.Number, event.ID, err)
+		return nil
+	}
+	return fmt.Errorf("%s reviewer sync: %s on %s/%s#%d: %w", s.provider.Name(), op, ref.Owner, ref.Repo, ref.Number, err)
+}
@@ -0,0 +1,178 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/codehost/github"
+	"pullrequest-manager/internal/infrastructure/database/memory"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type githubCall struct {
+	method    string
+	path      string
+	reviewers []string
+}
+
+// fakeGitHub fails the first failures requests with failStatus and accepts
+// the rest.
+type fakeGitHub struct {
+	mu         sync.Mutex
+	failures   int
+	failStatus int
+	calls      []githubCall
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Reviewers []string `json:"reviewers"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, githubCall{method: r.Method, path: r.URL.Path, reviewers: body.Reviewers})
+	if f.failures > 0 {
+		f.failures--
+		w.WriteHeader(f.failStatus)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func newReviewerSync(t *testing.T, fake *fakeGitHub) *services.ReviewerSync {
+	t.Helper()
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	users := memory.NewUserRepository()
+	identities := memory.NewUserIdentityRepository()
+	for _, u := range []*models.User{
+		{ExternalID: "u1", Username: "Alice", IsActive: true},
+		{ExternalID: "u2", Username: "Bob", IsActive: true},
+	} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bob, _ := users.FindByExternalID(ctx, "u2")
+	if err := identities.Upsert(ctx, &models.UserIdentity{Provider: models.ProviderGitHub, Login: "bob-gh", UserID: bob.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	return services.NewReviewerSync(github.NewClient(srv.URL, "token", nil), users, identities, services.ReviewerSyncOptions{
+		MaxAttempts: 3,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+}
+
+func reviewersChanged(t *testing.T, prID string, added []string, removed []string) services.Event {
+	t.Helper()
+
+	data, err := json.Marshal(dtos.ReviewersChangedEventDTO{PullRequestID: prID, Added: added, Removed: removed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return services.Event{
+		ID:         uuid.New(),
+		Type:       services.EventPullRequestReviewersChanged,
+		OccurredAt: time.Now(),
+		Data:       data,
+	}
+}
+
+func TestReviewerSyncPublishAppliesChange(t *testing.T) {
+	fake := &fakeGitHub{}
+	rs := newReviewerSync(t, fake)
+
+	// u1 has no linked identity, so its external ID is used as the login.
+	err := rs.Publish(context.Background(), reviewersChanged(t, "octo/api#5", []string{"u2"}, []string{"u1"}))
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	want := []githubCall{
+		{http.MethodDelete, "/repos/octo/api/pulls/5/requested_reviewers", []string{"u1"}},
+		{http.MethodPost, "/repos/octo/api/pulls/5/requested_reviewers", []string{"bob-gh"}},
+	}
+	if !slices.EqualFunc(fake.calls, want, equalCall) {
+		t.Errorf("calls = %+v, want %+v", fake.calls, want)
+	}
+}
+
+func TestReviewerSyncPublishRetriesTransientFailures(t *testing.T) {
+	fake := &fakeGitHub{failures: 2, failStatus: http.StatusBadGateway}
+	rs := newReviewerSync(t, fake)
+
+	err := rs.Publish(context.Background(), reviewersChanged(t, "octo/api#5", []string{"u2"}, nil))
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(fake.calls) != 3 {
+		t.Errorf("got %d calls, want 3", len(fake.calls))
+	}
+}
+
+func TestReviewerSyncPublishReturnsErrorWhenAttemptsRunOut(t *testing.T) {
+	fake := &fakeGitHub{failures: 10, failStatus: http.StatusServiceUnavailable}
+	rs := newReviewerSync(t, fake)
+
+	// The error keeps the event in the outbox for a later retry.
+	err := rs.Publish(context.Background(), reviewersChanged(t, "octo/api#5", []string{"u2"}, nil))
+	if err == nil {
+		t.Fatal("Publish succeeded, want an error")
+	}
+	if len(fake.calls) != 3 {
+		t.Errorf("got %d calls, want 3", len(fake.calls))
+	}
+}
+
+func TestReviewerSyncPublishDropsRejectedChanges(t *testing.T) {
+	fake := &fakeGitHub{failures: 1, failStatus: http.StatusUnprocessableEntity}
+	rs := newReviewerSync(t, fake)
+
+	err := rs.Publish(context.Background(), reviewersChanged(t, "octo/api#5", []string{"u2"}, nil))
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("got %d calls, want 1", len(fake.calls))
+	}
+}
+
+func TestReviewerSyncPublishIgnoresOtherEvents(t *testing.T) {
+	fake := &fakeGitHub{}
+	rs := newReviewerSync(t, fake)
+
+	events := []services.Event{
+		reviewersChanged(t, "pr-1001", []string{"u2"}, nil),
+		reviewersChanged(t, "group/project!3", []string{"u2"}, nil),
+		reviewersChanged(t, "octo/api#5", []string{"unknown"}, nil),
+		{ID: uuid.New(), Type: services.EventPullRequestMerged, Data: json.RawMessage(`{}`)},
+	}
+	for _, e := range events {
+		if err := rs.Publish(context.Background(), e); err != nil {
+			t.Errorf("Publish(%s): %v", e.Data, err)
+		}
+	}
+	if len(fake.calls) != 0 {
+		t.Errorf("calls = %+v, want none", fake.calls)
+	}
+}
+
+func equalCall(a, b githubCall) bool {
+	return a.method == b.method && a.path == b.path && slices.Equal(a.reviewers, b.reviewers)
+}
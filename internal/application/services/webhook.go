@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/publicnet"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidWebhook  = errors.New("invalid webhook subscription")
+	ErrWebhookNotFound = errors.New("webhook subscription not found")
+)
+
+const webhookSecretBytes = 32
+
+type DefaultWebhookService struct {
+	subscriptions repositories.WebhookSubscription
+}
+
+func NewDefaultWebhookService(subscriptions repositories.WebhookSubscription) *DefaultWebhookService {
+	return &DefaultWebhookService{subscriptions: subscriptions}
+}
+
+// AddWebhook registers a subscription. When no secret is supplied one is
+// generated; the secret is only ever returned by this call.
+func (s *DefaultWebhookService) AddWebhook(ctx context.Context, req dtos.WebhookCreateRequestDTO) (*dtos.WebhookDTO, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	// The dispatcher refuses non-public addresses when it connects; literal
+	// ones are rejected here already so the subscription is never stored.
+	if addr, err := netip.ParseAddr(u.Hostname()); (err == nil && !publicnet.IsPublic(addr)) || strings.EqualFold(u.Hostname(), "localhost") {
+		return nil, fmt.Errorf("%w: url must point to a public address", ErrInvalidWebhook)
+	}
+	for _, t := range req.Events {
+		if !isKnownEventType(t) {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, t)
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		buf := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("generate webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.Events,
+	}
+	if err := s.subscriptions.Create(ctx, sub); err != nil {
+		return nil, fmt.Errorf("create webhook subscription: %w", err)
+	}
+
+	dto := convertWebhookToDTO(sub)
+	dto.Secret = secret
+	return &dto, nil
+}
+
+func (s *DefaultWebhookService) ListWebhooks(ctx context.Context) ([]dtos.WebhookDTO, error) {
+	subs, err := s.subscriptions.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+
+	list := make([]dtos.WebhookDTO, 0, len(subs))
+	for _, sub := range subs {
+		list = append(list, convertWebhookToDTO(sub))
+	}
+	return list, nil
+}
+
+func (s *DefaultWebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	subID, err := uuid.Parse(id)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+
+	err = s.subscriptions.DeleteByID(ctx, subID)
+	if errors.Is(err, repositories.ErrWebhookNotFound) {
+		return ErrWebhookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func convertWebhookToDTO(sub *models.WebhookSubscription) dtos.WebhookDTO {
+	events := sub.EventTypes
+	if events == nil {
+		events = []string{}
+	}
+	return dtos.WebhookDTO{
+		ID:        sub.ID.String(),
+		URL:       sub.URL,
+		Events:    events,
+		CreatedAt: sub.CreatedAt,
+	}
+}
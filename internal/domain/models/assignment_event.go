@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AssignmentEventType string
+
+const (
+	EventAssign   AssignmentEventType = "ASSIGN"
+	EventReassign AssignmentEventType = "REASSIGN"
+	EventUnassign AssignmentEventType = "UNASSIGN"
+	EventMerge    AssignmentEventType = "MERGE"
+)
+
+// AssignmentEvent is one entry of a pull request's append-only reviewer
+// history. ReviewerID is the reviewer gained and PreviousReviewerID the one
+// lost; either is nil when it does not apply to the event type.
+type AssignmentEvent struct {
+	ID                 int64               `db:"id"`
+	PullRequestID      uuid.UUID           `db:"pull_request_id"`
+	Type               AssignmentEventType `db:"event_type"`
+	ReviewerID         *uuid.UUID          `db:"reviewer_id"`
+	PreviousReviewerID *uuid.UUID          `db:"previous_reviewer_id"`
+	Actor              string              `db:"actor"`
+	Reason             string              `db:"reason"`
+	CreatedAt          time.Time           `db:"created_at"`
+}
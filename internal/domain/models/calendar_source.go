@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarSource is an iCalendar feed whose events are imported as a user's
+// unavailability periods. URL is empty for an uploaded file, which is only
+// re-imported by another upload.
+type CalendarSource struct {
+	ID           uuid.UUID  `db:"id"`
+	UserID       uuid.UUID  `db:"user_id"`
+	URL          string     `db:"url"`
+	LastSyncedAt *time.Time `db:"last_synced_at"`
+	LastError    string     `db:"last_error"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
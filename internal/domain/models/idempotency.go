@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// IdempotencyRecord remembers the outcome of a request made with an
+// Idempotency-Key. StatusCode is zero while the first request is still being
+// processed.
+type IdempotencyRecord struct {
+	Scope           string            `db:"scope"`
+	Key             string            `db:"key"`
+	RequestHash     string            `db:"request_hash"`
+	StatusCode      int               `db:"status_code"`
+	ResponseHeaders map[string]string `db:"response_headers"`
+	ResponseBody    []byte            `db:"response_body"`
+	CreatedAt       time.Time         `db:"created_at"`
+	ExpiresAt       time.Time         `db:"expires_at"`
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
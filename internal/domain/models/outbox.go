@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is an event stored in the same transaction as the change that
+// caused it and later handed to publishers by the outbox relay.
+type OutboxEvent struct {
+	ID uuid.UUID `db:"id"`
+	// AggregateID is the pull request the event belongs to. Events of one
+	// aggregate are published in the order they occurred.
+	AggregateID uuid.UUID  `db:"aggregate_id"`
+	EventType   string     `db:"event_type"`
+	Payload     []byte     `db:"payload"`
+	OccurredAt  time.Time  `db:"occurred_at"`
+	PublishedAt *time.Time `db:"published_at"`
+	Attempts    int        `db:"attempts"`
+	LastError   string     `db:"last_error"`
+	// NextAttemptAt delays the retry of an event whose last attempt failed,
+	// and leases a claimed event to one relay while it is being published.
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+}
@@ -7,13 +7,61 @@ import (
 )
 
 type PullRequest struct {
-	ID        uuid.UUID  `db:"id"`
-	Title     string     `db:"title"`
-	AuthorID  uuid.UUID  `db:"author_id"`
-	StatusID  uuid.UUID  `db:"status_id"`
-	MergedAt  *time.Time `db:"merged_at"`
-	CreatedAt time.Time  `db:"created_at"`
-	UpdatedAt time.Time  `db:"updated_at"`
+	ID         uuid.UUID  `db:"id"`
+	ExternalID string     `db:"external_id"`
+	Title      string     `db:"title"`
+	AuthorID   uuid.UUID  `db:"author_id"`
+	StatusID   uuid.UUID  `db:"status_id"`
+	MergedAt   *time.Time `db:"merged_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	// Version increases with every update and guards against lost updates.
+	Version int64 `db:"version"`
 
 	ReviewersIDs []uuid.UUID
+	// Reviews holds submitted verdicts keyed by reviewer. Reviewers without an
+	// entry are PENDING.
+	Reviews map[uuid.UUID]Review
+	// AssignmentEvents are not yet persisted. Repositories append them to the
+	// history together with the PR and then clear the slice.
+	AssignmentEvents []AssignmentEvent
+	// PendingEvents go to the outbox in the same transaction as the PR and are
+	// cleared once saved.
+	PendingEvents []OutboxEvent
+}
+
+func (pr *PullRequest) RecordEvent(event AssignmentEvent) {
+	pr.AssignmentEvents = append(pr.AssignmentEvents, event)
+}
+
+func (pr *PullRequest) AddPendingEvent(event OutboxEvent) {
+	pr.PendingEvents = append(pr.PendingEvents, event)
+}
+
+func (pr *PullRequest) Review(reviewerID uuid.UUID) Review {
+	if r, ok := pr.Reviews[reviewerID]; ok {
+		return r
+	}
+	return Review{ReviewerID: reviewerID, Verdict: VerdictPending}
+}
+
+func (pr *PullRequest) SetVerdict(reviewerID uuid.UUID, verdict ReviewVerdict, at time.Time) {
+	if pr.Reviews == nil {
+		pr.Reviews = make(map[uuid.UUID]Review)
+	}
+	pr.Reviews[reviewerID] = Review{ReviewerID: reviewerID, Verdict: verdict, VerdictAt: &at}
+}
+
+// ApprovalState counts approvals and outstanding change requests among the
+// currently assigned reviewers only.
+func (pr *PullRequest) ApprovalState() (approvals int, changesRequested int) {
+	for _, rid := range pr.ReviewersIDs {
+		switch pr.Review(rid).Verdict {
+		case VerdictApproved:
+			approvals++
+		case VerdictChangesRequested:
+			changesRequested++
+		}
+	}
+	return approvals, changesRequested
 }
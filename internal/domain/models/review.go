@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReviewVerdict string
+
+const (
+	VerdictPending          ReviewVerdict = "PENDING"
+	VerdictApproved         ReviewVerdict = "APPROVED"
+	VerdictChangesRequested ReviewVerdict = "CHANGES_REQUESTED"
+	VerdictCommented        ReviewVerdict = "COMMENTED"
+)
+
+func (v ReviewVerdict) IsValid() bool {
+	switch v {
+	case VerdictPending, VerdictApproved, VerdictChangesRequested, VerdictCommented:
+		return true
+	default:
+		return false
+	}
+}
+
+type Review struct {
+	ReviewerID uuid.UUID     `db:"reviewer_id"`
+	Verdict    ReviewVerdict `db:"verdict"`
+	VerdictAt  *time.Time    `db:"verdict_at"`
+}
@@ -1,10 +1,56 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/google/uuid"
 )
 
+var ErrInvalidStatusTransition = errors.New("invalid pull request status transition")
+
+type StatusName string
+
+const (
+	StatusDraft  StatusName = "DRAFT"
+	StatusOpen   StatusName = "OPEN"
+	StatusMerged StatusName = "MERGED"
+	StatusClosed StatusName = "CLOSED"
+)
+
+var StatusNames = []StatusName{StatusDraft, StatusOpen, StatusMerged, StatusClosed}
+
+// statusTransitions lists every allowed move of the PR lifecycle: a draft is
+// published or abandoned, an open PR is merged or closed, and a closed PR can
+// be reopened. MERGED is terminal.
+var statusTransitions = map[StatusName][]StatusName{
+	StatusDraft:  {StatusOpen, StatusClosed},
+	StatusOpen:   {StatusMerged, StatusClosed},
+	StatusClosed: {StatusOpen},
+	StatusMerged: {},
+}
+
+func (s StatusName) CanTransitionTo(to StatusName) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (s StatusName) ValidateTransition(to StatusName) error {
+	if !s.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, s, to)
+	}
+	return nil
+}
+
 type Status struct {
 	ID   uuid.UUID `db:"id"`
 	Name string    `db:"name"`
 }
+
+func (s *Status) StatusName() StatusName {
+	return StatusName(s.Name)
+}
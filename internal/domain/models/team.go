@@ -11,6 +11,8 @@ type Team struct {
 	Name      string    `db:"name"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+	// Version increases with every update and guards against lost updates.
+	Version int64 `db:"version"`
 
 	UserIDs []uuid.UUID
 }
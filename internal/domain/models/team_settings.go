@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultReviewerCount = 2
+	DefaultMinReviewers  = 1
+)
+
+type TeamSettings struct {
+	TeamID            uuid.UUID `db:"team_id"`
+	ReviewerCount     int       `db:"reviewer_count"`
+	MinReviewers      int       `db:"min_reviewers"`
+	RequiredApprovals int       `db:"required_approvals"`
+	SelectionStrategy string    `db:"selection_strategy"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
+
+func DefaultTeamSettings(teamID uuid.UUID) *TeamSettings {
+	return &TeamSettings{
+		TeamID:        teamID,
+		ReviewerCount: DefaultReviewerCount,
+		MinReviewers:  DefaultMinReviewers,
+	}
+}
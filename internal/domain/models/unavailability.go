@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserUnavailability is a period in which a user must not be picked as a
+// reviewer, such as a vacation. The period covers [StartsAt, EndsAt).
+// SourceID is set for periods imported from a calendar feed.
+type UserUnavailability struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    uuid.UUID  `db:"user_id"`
+	StartsAt  time.Time  `db:"starts_at"`
+	EndsAt    time.Time  `db:"ends_at"`
+	Reason    string     `db:"reason"`
+	SourceID  *uuid.UUID `db:"source_id"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+func (u *UserUnavailability) Covers(at time.Time) bool {
+	return !at.Before(u.StartsAt) && at.Before(u.EndsAt)
+}
@@ -7,11 +7,12 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `db:"id"`
-	Username  string    `db:"username"`
-	IsActive  bool      `db:"is_active"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID         uuid.UUID `db:"id"`
+	ExternalID string    `db:"external_id"`
+	Username   string    `db:"username"`
+	IsActive   bool      `db:"is_active"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
 
 	TeamIDs []uuid.UUID
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+var Providers = []string{ProviderGitHub, ProviderGitLab}
+
+// UserIdentity links a user to their login on a code host.
+type UserIdentity struct {
+	Provider  string    `db:"provider"`
+	Login     string    `db:"login"`
+	UserID    uuid.UUID `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
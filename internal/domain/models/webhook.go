@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription receives every event whose type is listed in
+// EventTypes, or every event when EventTypes is empty.
+type WebhookSubscription struct {
+	ID         uuid.UUID `db:"id"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes []string  `db:"event_types"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+func (s *WebhookSubscription) Wants(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeadLetter struct {
+	ID             uuid.UUID  `db:"id"`
+	SubscriptionID *uuid.UUID `db:"subscription_id"`
+	EventID        uuid.UUID  `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	URL            string     `db:"url"`
+	Payload        []byte     `db:"payload"`
+	Attempts       int        `db:"attempts"`
+	LastError      string     `db:"last_error"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
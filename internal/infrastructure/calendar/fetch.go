@@ -0,0 +1,82 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"pullrequest-manager/internal/infrastructure/publicnet"
+	"strings"
+	"time"
+)
+
+// MaxFeedSize caps downloaded and uploaded feeds.
+const MaxFeedSize = 5 << 20
+
+var (
+	// ErrForbiddenDestination is returned for feeds on loopback, private,
+	// link-local or otherwise non-public addresses.
+	ErrForbiddenDestination = publicnet.ErrForbiddenDestination
+	ErrFeedTooLarge         = fmt.Errorf("feed exceeds %d bytes", MaxFeedSize)
+)
+
+// StatusError reports a feed server that answered with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("feed server responded with status %d", e.StatusCode)
+}
+
+type HTTPFetcher struct {
+	http *http.Client
+}
+
+// NewHTTPFetcher returns a fetcher using httpClient as is. With a nil client,
+// it only connects to public addresses, so that user-supplied feed URLs
+// cannot reach services on the internal network.
+func NewHTTPFetcher(httpClient *http.Client) *HTTPFetcher {
+	if httpClient == nil {
+		httpClient = publicnet.NewClient(30 * time.Second)
+	}
+	return &HTTPFetcher{http: httpClient}
+}
+
+// Fetch downloads a feed. webcal:// URLs, as published by most calendar apps,
+// are fetched over https.
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse feed URL: %w", err)
+	}
+	if strings.EqualFold(u.Scheme, "webcal") {
+		u.Scheme = "https"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s: %w", u.Redacted(), &StatusError{StatusCode: resp.StatusCode})
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxFeedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read feed: %w", err)
+	}
+	if len(body) > MaxFeedSize {
+		return nil, ErrFeedTooLarge
+	}
+	return body, nil
+}
@@ -0,0 +1,103 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func serveFixture(t *testing.T, name string) http.HandlerFunc {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/calendar" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write(data)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(serveFixture(t, "monthly.ics"))
+	defer srv.Close()
+
+	data, err := NewHTTPFetcher(srv.Client()).Fetch(context.Background(), srv.URL+"/feed.ics")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	cal, err := Parse(bytes.NewReader(data))
+	if err != nil || len(cal.Events) != 1 {
+		t.Fatalf("Parse = %+v, %v", cal, err)
+	}
+}
+
+func TestFetchWebcalUsesHTTPS(t *testing.T) {
+	srv := httptest.NewTLSServer(serveFixture(t, "monthly.ics"))
+	defer srv.Close()
+
+	webcalURL := "webcal" + strings.TrimPrefix(srv.URL, "https")
+	if _, err := NewHTTPFetcher(srv.Client()).Fetch(context.Background(), webcalURL); err != nil {
+		t.Fatalf("Fetch(%s): %v", webcalURL, err)
+	}
+}
+
+func TestFetchErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		check   func(error) bool
+	}{
+		{
+			"status",
+			func(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) },
+			func(err error) bool {
+				var statusErr *StatusError
+				return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+			},
+		},
+		{
+			"too large",
+			func(w http.ResponseWriter, r *http.Request) { w.Write(make([]byte, MaxFeedSize+1)) },
+			func(err error) bool { return errors.Is(err, ErrFeedTooLarge) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			_, err := NewHTTPFetcher(srv.Client()).Fetch(context.Background(), srv.URL)
+			if !tt.check(err) {
+				t.Errorf("Fetch = %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchRejectsNonPublicDestinations(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	fetcher := NewHTTPFetcher(nil)
+	for _, rawURL := range []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)} {
+		_, err := fetcher.Fetch(context.Background(), rawURL)
+		if !errors.Is(err, ErrForbiddenDestination) {
+			t.Errorf("Fetch(%s) = %v, want ErrForbiddenDestination", rawURL, err)
+		}
+	}
+	if hits != 0 {
+		t.Errorf("server got %d requests, want none", hits)
+	}
+}
@@ -0,0 +1,452 @@
+// Package calendar reads iCalendar (RFC 5545) feeds and turns their VEVENTs,
+// including all-day and recurring ones, into concrete time ranges.
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNotCalendar = errors.New("not an iCalendar document")
+
+// Event is a VEVENT reduced to what is needed to know when it takes place.
+// End is exclusive.
+type Event struct {
+	UID          string
+	Summary      string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Rule         *Rule
+	RDates       []time.Time
+	ExDates      []time.Time
+	RecurrenceID *time.Time
+	Cancelled    bool
+}
+
+// Occurrence is a single instance of an event.
+type Occurrence struct {
+	Start   time.Time
+	End     time.Time
+	Summary string
+}
+
+// Calendar holds the usable events of a feed. Events that could not be read
+// are left out and reported in Skipped, one error each.
+type Calendar struct {
+	Events  []Event
+	Skipped []error
+}
+
+// Parse reads an iCalendar document. Floating times and all-day dates are
+// interpreted in UTC, as are times whose TZID is not a known IANA zone.
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{}
+	var (
+		stack   []string
+		props   []property
+		sawRoot bool
+	)
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseProperty(line)
+		if err != nil {
+			if len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+				props = append(props, property{name: "X-INVALID", value: line})
+			}
+			continue
+		}
+
+		switch p.name {
+		case "BEGIN":
+			component := strings.ToUpper(p.value)
+			if component == "VCALENDAR" && len(stack) == 0 {
+				sawRoot = true
+			}
+			if component == "VEVENT" {
+				props = props[:0]
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 {
+				continue
+			}
+			if stack[len(stack)-1] == "VEVENT" {
+				ev, err := buildEvent(props)
+				if err != nil {
+					cal.Skipped = append(cal.Skipped, err)
+				} else {
+					cal.Events = append(cal.Events, *ev)
+				}
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			// Properties of nested components such as VALARM are ignored.
+			if len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+				props = append(props, p)
+			}
+		}
+	}
+	if !sawRoot {
+		return nil, ErrNotCalendar
+	}
+
+	return cal, nil
+}
+
+// Occurrences expands every event into the instances that overlap
+// [from, to). Instances moved or cancelled through RECURRENCE-ID replace the
+// ones the rule would produce.
+func (c *Calendar) Occurrences(from, to time.Time) []Occurrence {
+	overrides := make(map[string][]time.Time)
+	for _, ev := range c.Events {
+		if ev.RecurrenceID != nil {
+			overrides[ev.UID] = append(overrides[ev.UID], *ev.RecurrenceID)
+		}
+	}
+
+	var list []Occurrence
+	for _, ev := range c.Events {
+		if ev.Cancelled {
+			continue
+		}
+		if ev.RecurrenceID == nil {
+			ev.ExDates = slices.Concat(ev.ExDates, overrides[ev.UID])
+		}
+		list = append(list, ev.occurrences(from, to)...)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].Start.Equal(list[j].Start) {
+			return list[i].Start.Before(list[j].Start)
+		}
+		return list[i].End.Before(list[j].End)
+	})
+	return list
+}
+
+func (e *Event) occurrences(from, to time.Time) []Occurrence {
+	duration := e.End.Sub(e.Start)
+	if duration <= 0 {
+		return nil
+	}
+
+	starts := []time.Time{e.Start}
+	if e.Rule != nil && e.RecurrenceID == nil {
+		starts = e.Rule.expand(e.Start, to)
+	}
+	starts = append(starts, e.RDates...)
+
+	var list []Occurrence
+	seen := make(map[int64]bool, len(starts))
+	for _, start := range starts {
+		end := start.Add(duration)
+		if !start.Before(to) || !end.After(from) || seen[start.UnixNano()] || containsTime(e.ExDates, start) {
+			continue
+		}
+		seen[start.UnixNano()] = true
+		list = append(list, Occurrence{Start: start, End: end, Summary: e.Summary})
+	}
+	return list
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfold joins continuation lines, which start with a space or a tab.
+func unfold(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits "NAME;PARAM=VALUE;...:VALUE". Parameter values may be
+// quoted and contain ':' or ';'.
+func parseProperty(line string) (property, error) {
+	p := property{params: make(map[string]string)}
+
+	inQuotes := false
+	valueAt := -1
+	var segments []string
+	segStart := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ';', ':':
+			if inQuotes {
+				continue
+			}
+			segments = append(segments, line[segStart:i])
+			segStart = i + 1
+			if line[i] == ':' {
+				valueAt = i + 1
+			}
+		}
+		if valueAt >= 0 {
+			break
+		}
+	}
+	if valueAt < 0 || len(segments) == 0 || segments[0] == "" {
+		return p, fmt.Errorf("malformed content line %q", line)
+	}
+
+	p.name = strings.ToUpper(segments[0])
+	for _, seg := range segments[1:] {
+		k, v, ok := strings.Cut(seg, "=")
+		if !ok {
+			continue
+		}
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	p.value = line[valueAt:]
+	return p, nil
+}
+
+func buildEvent(props []property) (*Event, error) {
+	var (
+		ev       Event
+		hasStart bool
+		hasEnd   bool
+		duration *time.Duration
+		rrule    string
+	)
+	for _, p := range props {
+		var err error
+		switch p.name {
+		case "UID":
+			ev.UID = p.value
+		case "SUMMARY":
+			ev.Summary = unescapeText(p.value)
+		case "STATUS":
+			ev.Cancelled = strings.EqualFold(p.value, "CANCELLED")
+		case "DTSTART":
+			ev.Start, ev.AllDay, err = parseTime(p.value, p.params)
+			hasStart = err == nil
+		case "DTEND":
+			ev.End, _, err = parseTime(p.value, p.params)
+			hasEnd = err == nil
+		case "DURATION":
+			var d time.Duration
+			d, err = parseDuration(p.value)
+			duration = &d
+		case "RRULE":
+			rrule = p.value
+		case "RDATE":
+			if strings.EqualFold(p.params["VALUE"], "PERIOD") {
+				continue
+			}
+			var dates []time.Time
+			dates, err = parseTimeList(p.value, p.params)
+			ev.RDates = append(ev.RDates, dates...)
+		case "EXDATE":
+			var dates []time.Time
+			dates, err = parseTimeList(p.value, p.params)
+			ev.ExDates = append(ev.ExDates, dates...)
+		case "RECURRENCE-ID":
+			var t time.Time
+			t, _, err = parseTime(p.value, p.params)
+			ev.RecurrenceID = &t
+		case "X-INVALID":
+			err = fmt.Errorf("malformed content line %q", p.value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %s: %w", ev.UID, p.name, err)
+		}
+	}
+
+	if !hasStart {
+		return nil, fmt.Errorf("event %q: DTSTART is missing", ev.UID)
+	}
+	switch {
+	case hasEnd:
+	case duration != nil:
+		ev.End = ev.Start.Add(*duration)
+	case ev.AllDay:
+		ev.End = ev.Start.AddDate(0, 0, 1)
+	default:
+		ev.End = ev.Start
+	}
+	if ev.End.Before(ev.Start) {
+		return nil, fmt.Errorf("event %q: ends before it starts", ev.UID)
+	}
+
+	if rrule != "" {
+		rule, err := ParseRule(rrule, ev.Start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("event %q: RRULE: %w", ev.UID, err)
+		}
+		ev.Rule = rule
+	}
+
+	return &ev, nil
+}
+
+func parseTimeList(value string, params map[string]string) ([]time.Time, error) {
+	var list []time.Time
+	for _, v := range strings.Split(value, ",") {
+		t, _, err := parseTime(v, params)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+// parseTime reads DATE and DATE-TIME values and reports whether the value was
+// a date.
+func parseTime(value string, params map[string]string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	loc := location(params["TZID"])
+
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, time.UTC)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t, false, nil
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	return t, false, nil
+}
+
+func location(tzid string) *time.Location {
+	if tzid == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(strings.TrimPrefix(tzid, "/"))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseDuration reads RFC 5545 durations such as "P1D", "PT1H30M" or "P2W".
+// Days are taken as 24 hours.
+func parseDuration(value string) (time.Duration, error) {
+	s := strings.TrimSpace(value)
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour,
+		'D': 24 * time.Hour,
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+	}
+
+	var (
+		total  time.Duration
+		inTime bool
+		digits int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 'T':
+			inTime = true
+			digits = i + 1
+		case c >= '0' && c <= '9':
+		default:
+			unit, ok := units[c]
+			if !ok || (c == 'M' && !inTime) || (inTime && (c == 'W' || c == 'D')) {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			n, err := strconv.Atoi(s[digits:i])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			total += time.Duration(n) * unit
+			digits = i + 1
+		}
+	}
+	if digits != len(s) {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return sign * total, nil
+}
+
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func containsTime(list []time.Time, t time.Time) bool {
+	for _, v := range list {
+		if v.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package calendar
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func parseFixture(t *testing.T, name string) *Calendar {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cal, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return cal
+}
+
+func utc(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func checkOccurrences(t *testing.T, got []Occurrence, want []Occurrence) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d occurrences, want %d:\n got %v\nwant %v", len(got), len(want), got, want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) || got[i].Summary != want[i].Summary {
+			t.Errorf("occurrence %d = %v – %v %q; want %v – %v %q",
+				i, got[i].Start.UTC(), got[i].End.UTC(), got[i].Summary, want[i].Start, want[i].End, want[i].Summary)
+		}
+	}
+}
+
+func TestOccurrencesRecurring(t *testing.T) {
+	cal := parseFixture(t, "recurring.ics")
+	if len(cal.Skipped) != 0 {
+		t.Fatalf("Skipped = %v", cal.Skipped)
+	}
+
+	// Berlin is at UTC+1 until 30 March and at UTC+2 after. The 10 March
+	// instance is excluded, the 12 March one moved and the 17 March one
+	// cancelled.
+	checkOccurrences(t, cal.Occurrences(utc("2025-03-01T00:00:00Z"), utc("2025-04-01T00:00:00Z")), []Occurrence{
+		{utc("2025-03-03T09:00:00Z"), utc("2025-03-03T09:30:00Z"), "Standup"},
+		{utc("2025-03-05T09:00:00Z"), utc("2025-03-05T09:30:00Z"), "Standup"},
+		{utc("2025-03-12T13:00:00Z"), utc("2025-03-12T13:30:00Z"), "Standup (moved)"},
+		{utc("2025-03-19T09:00:00Z"), utc("2025-03-19T09:30:00Z"), "Standup"},
+		{utc("2025-03-28T09:00:00Z"), utc("2025-03-28T10:00:00Z"), "Retro"},
+		{utc("2025-03-29T09:00:00Z"), utc("2025-03-29T10:00:00Z"), "Retro"},
+		{utc("2025-03-30T08:00:00Z"), utc("2025-03-30T09:00:00Z"), "Retro"},
+		{utc("2025-03-31T08:00:00Z"), utc("2025-03-31T09:00:00Z"), "Retro"},
+	})
+}
+
+func TestOccurrencesWindow(t *testing.T) {
+	cal := parseFixture(t, "recurring.ics")
+
+	// An instance that only overlaps the window is included.
+	checkOccurrences(t, cal.Occurrences(utc("2025-03-05T09:15:00Z"), utc("2025-03-12T13:00:00Z")), []Occurrence{
+		{utc("2025-03-05T09:00:00Z"), utc("2025-03-05T09:30:00Z"), "Standup"},
+	})
+}
+
+func TestOccurrencesMonthlyLastWeekday(t *testing.T) {
+	cal := parseFixture(t, "monthly.ics")
+
+	var want []Occurrence
+	for _, day := range []string{"2025-01-31", "2025-02-28", "2025-03-28", "2025-04-25", "2025-05-30", "2025-06-27"} {
+		want = append(want, Occurrence{utc(day + "T15:00:00Z"), utc(day + "T16:00:00Z"), "Release review"})
+	}
+	checkOccurrences(t, cal.Occurrences(utc("2025-01-01T00:00:00Z"), utc("2026-01-01T00:00:00Z")), want)
+}
+
+func TestOccurrencesAllDay(t *testing.T) {
+	cal := parseFixture(t, "allday.ics")
+
+	checkOccurrences(t, cal.Occurrences(utc("2025-04-01T00:00:00Z"), utc("2025-05-01T00:00:00Z")), []Occurrence{
+		{utc("2025-04-07T00:00:00Z"), utc("2025-04-12T00:00:00Z"), "Vacation, Alps"},
+		{utc("2025-04-15T12:00:00Z"), utc("2025-04-15T13:30:00Z"), "Dentist appointment across a folded line"},
+		{utc("2025-04-21T00:00:00Z"), utc("2025-04-22T00:00:00Z"), "Day off"},
+	})
+
+	for _, ev := range cal.Events {
+		if want := ev.UID != "dentist@example.com"; ev.AllDay != want {
+			t.Errorf("event %q: AllDay = %v, want %v", ev.UID, ev.AllDay, want)
+		}
+	}
+}
+
+func TestParseSkipsUnreadableEvents(t *testing.T) {
+	cal := parseFixture(t, "allday.ics")
+
+	if len(cal.Skipped) != 2 {
+		t.Fatalf("Skipped = %v, want 2 errors", cal.Skipped)
+	}
+	if !strings.Contains(cal.Skipped[0].Error(), "DTSTART is missing") {
+		t.Errorf("Skipped[0] = %v", cal.Skipped[0])
+	}
+	if !errors.Is(cal.Skipped[1], ErrUnsupportedRule) {
+		t.Errorf("Skipped[1] = %v, want ErrUnsupportedRule", cal.Skipped[1])
+	}
+}
+
+func TestParseRejectsOtherDocuments(t *testing.T) {
+	_, err := Parse(strings.NewReader("BEGIN:VCARD\r\nFN:Alice\r\nEND:VCARD\r\n"))
+	if !errors.Is(err, ErrNotCalendar) {
+		t.Errorf("Parse = %v, want ErrNotCalendar", err)
+	}
+}
@@ -0,0 +1,399 @@
+package calendar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnsupportedRule = errors.New("unsupported recurrence rule")
+
+// maxPeriods bounds the work spent on rules that started long before the
+// requested window.
+const maxPeriods = 100000
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// WeekdayNum is a BYDAY entry such as "MO", "2TU" or "-1FR". N is zero when
+// every matching weekday is meant.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule is the subset of RFC 5545 RRULE used by calendar exports for absences:
+// FREQ from DAILY to YEARLY with INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY,
+// BYMONTH and WKST.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// ParseRule reads an RRULE value. A floating or date UNTIL is taken in loc,
+// the location of the event's DTSTART.
+func ParseRule(value string, loc *time.Location) (*Rule, error) {
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed rule part %q", part)
+		}
+		key = strings.ToUpper(key)
+
+		var err error
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(val))
+			switch r.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				return nil, fmt.Errorf("%w: FREQ=%s", ErrUnsupportedRule, val)
+			}
+		case "INTERVAL":
+			r.Interval, err = positiveInt(val)
+		case "COUNT":
+			r.Count, err = positiveInt(val)
+		case "UNTIL":
+			var until time.Time
+			var isDate bool
+			until, isDate, err = parseTime(val, nil)
+			if err == nil {
+				if isDate {
+					y, m, d := until.Date()
+					until = time.Date(y, m, d, 23, 59, 59, 0, loc)
+				} else if !strings.HasSuffix(val, "Z") {
+					until = time.Date(until.Year(), until.Month(), until.Day(),
+						until.Hour(), until.Minute(), until.Second(), 0, loc)
+				}
+				r.Until = &until
+			}
+		case "BYDAY":
+			r.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseIntList(val, 1, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseIntList(val, 1, 12)
+			for _, m := range months {
+				if m < 0 {
+					err = fmt.Errorf("invalid BYMONTH %q", val)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(val)]
+			if !ok {
+				err = fmt.Errorf("invalid WKST %q", val)
+			}
+			r.WeekStart = day
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedRule, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, errors.New("FREQ is missing")
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT and UNTIL are mutually exclusive")
+	}
+	if r.Freq == Daily || r.Freq == Weekly {
+		for _, wd := range r.ByDay {
+			if wd.N != 0 {
+				return nil, fmt.Errorf("numbered BYDAY is not allowed with FREQ=%s", r.Freq)
+			}
+		}
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return nil, errors.New("BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+
+	return r, nil
+}
+
+// expand returns the instance starts of an event beginning at dtstart, up to
+// but excluding to. DTSTART is always the first instance.
+func (r *Rule) expand(dtstart time.Time, to time.Time) []time.Time {
+	starts := []time.Time{dtstart}
+	if r.Count == 1 {
+		return starts
+	}
+
+	for i := 0; i < maxPeriods; i++ {
+		periodStart, candidates := r.period(dtstart, i)
+		if !periodStart.Before(to) || (r.Until != nil && periodStart.After(*r.Until)) {
+			return starts
+		}
+
+		for _, c := range candidates {
+			if !c.After(dtstart) {
+				continue
+			}
+			if !c.Before(to) || (r.Until != nil && c.After(*r.Until)) {
+				return starts
+			}
+			starts = append(starts, c)
+			if r.Count > 0 && len(starts) >= r.Count {
+				return starts
+			}
+		}
+	}
+	return starts
+}
+
+// period returns the start of the i-th recurrence period and the sorted
+// instance candidates inside it, at DTSTART's time of day.
+func (r *Rule) period(dtstart time.Time, i int) (time.Time, []time.Time) {
+	loc := dtstart.Location()
+	y, m, d := dtstart.Date()
+	hh, mm, ss := dtstart.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hh, mm, ss, 0, loc)
+	}
+	step := i * r.Interval
+
+	var (
+		start time.Time
+		days  []time.Time
+	)
+	switch r.Freq {
+	case Daily:
+		start = at(y, m, d+step)
+		if r.matchesMonthDay(start) && r.matchesWeekday(start) {
+			days = append(days, start)
+		}
+	case Weekly:
+		offset := (int(dtstart.Weekday()) - int(r.WeekStart) + 7) % 7
+		start = at(y, m, d-offset+7*step)
+		for k := 0; k < 7; k++ {
+			day := at(start.Year(), start.Month(), start.Day()+k)
+			if (len(r.ByDay) == 0 && day.Weekday() == dtstart.Weekday()) || r.matchesWeekdayStrict(day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		start = at(y, m+time.Month(step), 1)
+		days = r.monthDays(start.Year(), start.Month(), d, at)
+	case Yearly:
+		start = at(y+step, time.January, 1)
+		switch {
+		case len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && len(r.ByDay) > 0:
+			days = r.yearWeekdays(start.Year(), at)
+		case len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0:
+			if date := at(start.Year(), m, d); date.Month() == m {
+				days = append(days, date)
+			}
+		default:
+			months := r.ByMonth
+			if len(months) == 0 {
+				for mo := time.January; mo <= time.December; mo++ {
+					months = append(months, mo)
+				}
+			}
+			for _, mo := range months {
+				days = append(days, r.monthDays(start.Year(), mo, d, at)...)
+			}
+		}
+	}
+
+	days = filterMonths(days, r.ByMonth)
+	sort.Slice(days, func(a, b int) bool { return days[a].Before(days[b]) })
+	return start, days
+}
+
+// monthDays expands BYMONTHDAY and BYDAY within one month; with neither, the
+// day of DTSTART is used when the month has it.
+func (r *Rule) monthDays(y int, m time.Month, defaultDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	last := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	var days []time.Time
+	switch {
+	case len(r.ByDay) > 0:
+		for d := 1; d <= last; d++ {
+			day := at(y, m, d)
+			if r.matchesOrdinalWeekday(day, d, last) && r.matchesMonthDay(day) {
+				days = append(days, day)
+			}
+		}
+	case len(r.ByMonthDay) > 0:
+		for _, md := range r.ByMonthDay {
+			if md < 0 {
+				md = last + md + 1
+			}
+			if md >= 1 && md <= last {
+				days = append(days, at(y, m, md))
+			}
+		}
+	default:
+		if defaultDay <= last {
+			days = append(days, at(y, m, defaultDay))
+		}
+	}
+	return dedupe(days)
+}
+
+// yearWeekdays expands BYDAY across a whole year, where "20MO" is the 20th
+// Monday of the year.
+func (r *Rule) yearWeekdays(y int, at func(int, time.Month, int) time.Time) []time.Time {
+	last := time.Date(y, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+
+	var days []time.Time
+	for d := 1; d <= last; d++ {
+		day := at(y, time.January, d)
+		if r.matchesOrdinalWeekday(day, d, last) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// matchesOrdinalWeekday reports whether day, the pos-th of count days in its
+// period, is selected by BYDAY.
+func (r *Rule) matchesOrdinalWeekday(day time.Time, pos int, count int) bool {
+	for _, wd := range r.ByDay {
+		if day.Weekday() != wd.Day {
+			continue
+		}
+		switch {
+		case wd.N == 0:
+			return true
+		case wd.N > 0 && (pos-1)/7+1 == wd.N:
+			return true
+		case wd.N < 0 && (count-pos)/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesWeekday(day time.Time) bool {
+	return len(r.ByDay) == 0 || r.matchesWeekdayStrict(day)
+}
+
+func (r *Rule) matchesWeekdayStrict(day time.Time) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, md := range r.ByMonthDay {
+		if md == day.Day() || (md < 0 && last+md+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func filterMonths(days []time.Time, months []time.Month) []time.Time {
+	if len(months) == 0 {
+		return days
+	}
+	kept := days[:0]
+	for _, day := range days {
+		for _, m := range months {
+			if day.Month() == m {
+				kept = append(kept, day)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+func dedupe(days []time.Time) []time.Time {
+	sort.Slice(days, func(a, b int) bool { return days[a].Before(days[b]) })
+	kept := days[:0]
+	for i, day := range days {
+		if i == 0 || !day.Equal(days[i-1]) {
+			kept = append(kept, day)
+		}
+	}
+	return kept
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	var list []WeekdayNum
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", value)
+		}
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", value)
+		}
+		wd := WeekdayNum{Day: day}
+		if n := item[:len(item)-2]; n != "" {
+			num, err := strconv.Atoi(n)
+			if err != nil || num == 0 || num < -53 || num > 53 {
+				return nil, fmt.Errorf("invalid BYDAY %q", value)
+			}
+			wd.N = num
+		}
+		list = append(list, wd)
+	}
+	return list, nil
+}
+
+// parseIntList reads comma-separated values whose magnitude lies in
+// [lo, hi]; negative values count from the end.
+func parseIntList(value string, lo, hi int) ([]int, error) {
+	var list []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || n == 0 || max(n, -n) < lo || max(n, -n) > hi {
+			return nil, fmt.Errorf("invalid value %q", value)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+func positiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,129 @@
+// Package github implements services.CodeHostProvider against the GitHub REST
+// API. The base URL is configurable so GitHub Enterprise Server works too.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBaseURL = "https://api.github.com"
+
+	apiVersion = "2022-11-28"
+	userAgent  = "pullrequest-manager"
+)
+
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewClient(baseURL string, token string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    httpClient,
+	}
+}
+
+func (c *Client) Name() string {
+	return models.ProviderGitHub
+}
+
+// ParsePullRequestID accepts the "owner/repo#number" IDs the GitHub webhook
+// handler assigns.
+func (c *Client) ParsePullRequestID(prID string) (services.PullRequestRef, bool) {
+	repo, number, ok := strings.Cut(prID, "#")
+	if !ok {
+		return services.PullRequestRef{}, false
+	}
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return services.PullRequestRef{}, false
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		return services.PullRequestRef{}, false
+	}
+	return services.PullRequestRef{Owner: owner, Repo: name, Number: n}, true
+}
+
+func (c *Client) RequestReviewers(ctx context.Context, ref services.PullRequestRef, logins []string) error {
+	return c.requestedReviewers(ctx, http.MethodPost, ref, logins)
+}
+
+func (c *Client) RemoveReviewers(ctx context.Context, ref services.PullRequestRef, logins []string) error {
+	return c.requestedReviewers(ctx, http.MethodDelete, ref, logins)
+}
+
+func (c *Client) requestedReviewers(ctx context.Context, method string, ref services.PullRequestRef, logins []string) error {
+	body, err := json.Marshal(struct {
+		Reviewers []string `json:"reviewers"`
+	}{Reviewers: logins})
+	if err != nil {
+		return fmt.Errorf("encode reviewers: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/requested_reviewers",
+		c.baseURL, url.PathEscape(ref.Owner), url.PathEscape(ref.Repo), ref.Number)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	err = fmt.Errorf("%s %s: status %d: %s", method, endpoint, resp.StatusCode, bytes.TrimSpace(msg))
+	if isPermanent(resp) {
+		return fmt.Errorf("%w: %w", services.ErrCodeHostRejected, err)
+	}
+	return err
+}
+
+// isPermanent treats client errors as final, except timeouts and rate limits.
+// GitHub reports secondary rate limits as 403 with Retry-After or an exhausted
+// quota header.
+func isPermanent(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests:
+		return false
+	case resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"):
+		return false
+	}
+	return resp.StatusCode >= 400 && resp.StatusCode <= 499
+}
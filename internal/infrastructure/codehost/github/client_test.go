@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"pullrequest-manager/internal/application/services"
+	"slices"
+	"testing"
+)
+
+type recordedRequest struct {
+	method    string
+	path      string
+	reviewers []string
+	header    http.Header
+}
+
+// fakeGitHub answers requested_reviewers calls with status and records them.
+func fakeGitHub(t *testing.T, status int, header http.Header) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+
+	var requests []recordedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		requests = append(requests, recordedRequest{
+			method:    r.Method,
+			path:      r.URL.EscapedPath(),
+			reviewers: body.Reviewers,
+			header:    r.Header.Clone(),
+		})
+
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"message":"fake"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestParsePullRequestID(t *testing.T) {
+	c := NewClient("", "", nil)
+
+	tests := []struct {
+		id   string
+		want services.PullRequestRef
+		ok   bool
+	}{
+		{"octo/api#42", services.PullRequestRef{Owner: "octo", Repo: "api", Number: 42}, true},
+		{"octo/api#0", services.PullRequestRef{}, false},
+		{"octo/api#x", services.PullRequestRef{}, false},
+		{"octo#42", services.PullRequestRef{}, false},
+		{"group/sub/project!42", services.PullRequestRef{}, false},
+		{"pr-1001", services.PullRequestRef{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := c.ParsePullRequestID(tt.id)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParsePullRequestID(%q) = %+v, %v; want %+v, %v", tt.id, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRequestAndRemoveReviewers(t *testing.T) {
+	srv, requests := fakeGitHub(t, http.StatusCreated, nil)
+	c := NewClient(srv.URL+"/api/v3/", "secret-token", nil)
+	ref := services.PullRequestRef{Owner: "octo", Repo: "my repo", Number: 7}
+
+	if err := c.RequestReviewers(context.Background(), ref, []string{"alice", "bob"}); err != nil {
+		t.Fatalf("RequestReviewers: %v", err)
+	}
+	if err := c.RemoveReviewers(context.Background(), ref, []string{"carol"}); err != nil {
+		t.Fatalf("RemoveReviewers: %v", err)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(*requests))
+	}
+	wantPath := "/api/v3/repos/octo/my%20repo/pulls/7/requested_reviewers"
+	for i, want := range []struct {
+		method    string
+		reviewers []string
+	}{
+		{http.MethodPost, []string{"alice", "bob"}},
+		{http.MethodDelete, []string{"carol"}},
+	} {
+		got := (*requests)[i]
+		if got.method != want.method || got.path != wantPath || !slices.Equal(got.reviewers, want.reviewers) {
+			t.Errorf("request %d = %s %s %v; want %s %s %v",
+				i, got.method, got.path, got.reviewers, want.method, wantPath, want.reviewers)
+		}
+		if auth := got.header.Get("Authorization"); auth != "Bearer secret-token" {
+			t.Errorf("request %d: Authorization = %q", i, auth)
+		}
+		if v := got.header.Get("X-GitHub-Api-Version"); v != apiVersion {
+			t.Errorf("request %d: X-GitHub-Api-Version = %q", i, v)
+		}
+	}
+}
+
+func TestRequestReviewersErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    http.Header
+		permanent bool
+	}{
+		{"not a collaborator", http.StatusUnprocessableEntity, nil, true},
+		{"not found", http.StatusNotFound, nil, true},
+		{"forbidden", http.StatusForbidden, nil, true},
+		{"secondary rate limit", http.StatusForbidden, http.Header{"Retry-After": {"60"}}, false},
+		{"quota exhausted", http.StatusForbidden, http.Header{"X-Ratelimit-Remaining": {"0"}}, false},
+		{"too many requests", http.StatusTooManyRequests, nil, false},
+		{"server error", http.StatusBadGateway, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := fakeGitHub(t, tt.status, tt.header)
+			c := NewClient(srv.URL, "", nil)
+
+			err := c.RequestReviewers(context.Background(), services.PullRequestRef{Owner: "o", Repo: "r", Number: 1}, []string{"alice"})
+			if err == nil {
+				t.Fatal("RequestReviewers succeeded, want an error")
+			}
+			if got := errors.Is(err, services.ErrCodeHostRejected); got != tt.permanent {
+				t.Errorf("errors.Is(err, ErrCodeHostRejected) = %v, want %v (err: %v)", got, tt.permanent, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CalendarSourceRepository struct {
+	mu      sync.RWMutex
+	sources map[uuid.UUID]models.CalendarSource
+}
+
+func NewCalendarSourceRepository() *CalendarSourceRepository {
+	return &CalendarSourceRepository{sources: make(map[uuid.UUID]models.CalendarSource)}
+}
+
+func (r *CalendarSourceRepository) Upsert(ctx context.Context, source *models.CalendarSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.sources {
+		if s.UserID == source.UserID && s.URL == source.URL {
+			*source = cloneCalendarSource(&s)
+			return nil
+		}
+	}
+
+	now := time.Now()
+	source.ID = uuid.New()
+	source.LastSyncedAt = nil
+	source.LastError = ""
+	source.CreatedAt = now
+	source.UpdatedAt = now
+
+	r.sources[source.ID] = cloneCalendarSource(source)
+	return nil
+}
+
+func (r *CalendarSourceRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.CalendarSource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.sources[id]
+	if !ok {
+		return nil, repositories.ErrCalendarSourceNotFound
+	}
+	c := cloneCalendarSource(&s)
+	return &c, nil
+}
+
+func (r *CalendarSourceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.CalendarSource, error) {
+	return r.filter(func(s *models.CalendarSource) bool { return s.UserID == userID }), nil
+}
+
+func (r *CalendarSourceRepository) FindWithURL(ctx context.Context) ([]*models.CalendarSource, error) {
+	return r.filter(func(s *models.CalendarSource) bool { return s.URL != "" }), nil
+}
+
+func (r *CalendarSourceRepository) Update(ctx context.Context, source *models.CalendarSource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sources[source.ID]
+	if !ok {
+		return repositories.ErrCalendarSourceNotFound
+	}
+
+	stored.LastSyncedAt = source.LastSyncedAt
+	stored.LastError = source.LastError
+	stored.UpdatedAt = time.Now()
+	source.UpdatedAt = stored.UpdatedAt
+
+	r.sources[source.ID] = cloneCalendarSource(&stored)
+	return nil
+}
+
+func (r *CalendarSourceRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sources[id]; !ok {
+		return repositories.ErrCalendarSourceNotFound
+	}
+	delete(r.sources, id)
+	return nil
+}
+
+func (r *CalendarSourceRepository) filter(keep func(*models.CalendarSource) bool) []*models.CalendarSource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var list []*models.CalendarSource
+	for _, s := range r.sources {
+		if keep(&s) {
+			c := cloneCalendarSource(&s)
+			list = append(list, &c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID.String() < list[j].ID.String()
+	})
+	return list
+}
+
+func cloneCalendarSource(s *models.CalendarSource) models.CalendarSource {
+	c := *s
+	if s.LastSyncedAt != nil {
+		at := *s.LastSyncedAt
+		c.LastSyncedAt = &at
+	}
+	return c
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"pullrequest-manager/internal/domain/models"
+	"slices"
+	"sync"
+	"time"
+)
+
+type idempotencyKey struct {
+	scope string
+	key   string
+}
+
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[idempotencyKey]models.IdempotencyRecord
+}
+
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{records: make(map[idempotencyKey]models.IdempotencyRecord)}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, rec *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	k := idempotencyKey{rec.Scope, rec.Key}
+	if existing, ok := r.records[k]; ok {
+		expired := !existing.ExpiresAt.After(now)
+		stale := !existing.Completed() && existing.CreatedAt.Before(staleBefore)
+		if !expired && !stale {
+			c := cloneIdempotencyRecord(existing)
+			return &c, nil
+		}
+	}
+
+	rec.CreatedAt = now
+	rec.StatusCode = 0
+	rec.ResponseHeaders = nil
+	rec.ResponseBody = nil
+	r.records[k] = cloneIdempotencyRecord(*rec)
+	return nil, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, rec *models.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := idempotencyKey{rec.Scope, rec.Key}
+	existing, ok := r.records[k]
+	if !ok {
+		return nil
+	}
+	existing.StatusCode = rec.StatusCode
+	existing.ResponseHeaders = maps.Clone(rec.ResponseHeaders)
+	existing.ResponseBody = slices.Clone(rec.ResponseBody)
+	r.records[k] = existing
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, scope string, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := idempotencyKey{scope, key}
+	if existing, ok := r.records[k]; ok && !existing.Completed() {
+		delete(r.records, k)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for k, rec := range r.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(r.records, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func cloneIdempotencyRecord(rec models.IdempotencyRecord) models.IdempotencyRecord {
+	rec.ResponseHeaders = maps.Clone(rec.ResponseHeaders)
+	rec.ResponseBody = slices.Clone(rec.ResponseBody)
+	return rec
+}
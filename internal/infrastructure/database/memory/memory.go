@@ -0,0 +1,19 @@
+// Package memory provides thread-safe in-memory implementations of the
+// repositories interfaces. It mirrors the not-found semantics of the pg
+// package and is meant for local demos and tests.
+package memory
+
+import (
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+var ErrDuplicateKey = repositories.ErrDuplicateKey
+
+func cloneIDs(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return nil
+	}
+	return append([]uuid.UUID{}, ids...)
+}
@@ -0,0 +1,21 @@
+package memory_test
+
+import (
+	"pullrequest-manager/internal/infrastructure/database/memory"
+	"pullrequest-manager/internal/infrastructure/database/repotest"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		statuses := memory.NewStatusRepository()
+		outbox := memory.NewOutboxRepository()
+		return repotest.Repositories{
+			Users:        memory.NewUserRepository(),
+			Teams:        memory.NewTeamRepository(),
+			Statuses:     statuses,
+			PullRequests: memory.NewPullRequestRepository(statuses, outbox),
+			Outbox:       outbox,
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRepository keeps events in memory. Like the Postgres outbox it leases
+// claimed events and hands them out without holding its lock.
+type OutboxRepository struct {
+	mu     sync.Mutex
+	events []*models.OutboxEvent
+}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+func (r *OutboxRepository) Process(
+	ctx context.Context,
+	limit int,
+	maxAttempts int,
+	lease time.Duration,
+	retryDelay func(attempts int) time.Duration,
+	handle func(ctx context.Context, event *models.OutboxEvent) error,
+) (int, error) {
+	batch := r.claim(limit, maxAttempts, lease)
+
+	published := 0
+	failed := make(map[uuid.UUID]bool)
+	for _, e := range batch {
+		if ctx.Err() != nil || failed[e.AggregateID] {
+			r.update(e.ID, func(stored *models.OutboxEvent) {
+				stored.NextAttemptAt = time.Now()
+			})
+			continue
+		}
+		if err := handle(ctx, e); err != nil {
+			failed[e.AggregateID] = true
+			r.update(e.ID, func(stored *models.OutboxEvent) {
+				stored.Attempts++
+				stored.LastError = err.Error()
+				stored.NextAttemptAt = time.Now().Add(retryDelay(stored.Attempts))
+			})
+			continue
+		}
+		r.update(e.ID, func(stored *models.OutboxEvent) {
+			now := time.Now()
+			stored.Attempts++
+			stored.PublishedAt = &now
+			stored.LastError = ""
+		})
+		published++
+	}
+
+	if err := ctx.Err(); err != nil {
+		return published, err
+	}
+	return published, nil
+}
+
+// claim leases up to limit due events, skipping those whose aggregate has an
+// earlier event that is leased or waiting for a retry.
+func (r *OutboxRepository) claim(limit int, maxAttempts int, lease time.Duration) []*models.OutboxEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := r.events[:0]
+	for _, e := range r.events {
+		if e.PublishedAt == nil {
+			pending = append(pending, e)
+		}
+	}
+	r.events = pending
+	sort.SliceStable(r.events, func(i, j int) bool {
+		return r.events[i].OccurredAt.Before(r.events[j].OccurredAt)
+	})
+
+	now := time.Now()
+	blocked := make(map[uuid.UUID]bool)
+	var batch []*models.OutboxEvent
+	for _, e := range r.events {
+		if e.Attempts >= maxAttempts {
+			continue
+		}
+		if e.NextAttemptAt.After(now) {
+			blocked[e.AggregateID] = true
+			continue
+		}
+		if blocked[e.AggregateID] || len(batch) == limit {
+			continue
+		}
+		e.NextAttemptAt = now.Add(lease)
+		c := cloneOutboxEvent(e)
+		batch = append(batch, &c)
+	}
+	return batch
+}
+
+func (r *OutboxRepository) update(id uuid.UUID, change func(stored *models.OutboxEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.events {
+		if e.ID == id {
+			change(e)
+			return
+		}
+	}
+}
+
+// add is used by the pull request repository while it holds its own lock.
+func (r *OutboxRepository) add(aggregateID uuid.UUID, events []models.OutboxEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range events {
+		for _, existing := range r.events {
+			if existing.ID == e.ID {
+				return fmt.Errorf("add outbox event %s: %w", e.ID, ErrDuplicateKey)
+			}
+		}
+		c := cloneOutboxEvent(&e)
+		c.AggregateID = aggregateID
+		r.events = append(r.events, &c)
+	}
+	return nil
+}
+
+func cloneOutboxEvent(e *models.OutboxEvent) models.OutboxEvent {
+	c := *e
+	c.Payload = append([]byte(nil), e.Payload...)
+	return c
+}
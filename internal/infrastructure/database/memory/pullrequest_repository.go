@@ -0,0 +1,305 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PullRequestRepository struct {
+	mu           sync.RWMutex
+	pullRequests map[uuid.UUID]models.PullRequest
+	events       map[uuid.UUID][]models.AssignmentEvent
+	lastEventID  int64
+	statuses     *StatusRepository
+	outbox       *OutboxRepository
+}
+
+func NewPullRequestRepository(statuses *StatusRepository, outbox *OutboxRepository) *PullRequestRepository {
+	return &PullRequestRepository{
+		pullRequests: make(map[uuid.UUID]models.PullRequest),
+		events:       make(map[uuid.UUID][]models.AssignmentEvent),
+		statuses:     statuses,
+		outbox:       outbox,
+	}
+}
+
+func (r *PullRequestRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.pullRequests {
+		if existing.ExternalID == pr.ExternalID {
+			return fmt.Errorf("create pull request: external id %q: %w", pr.ExternalID, ErrDuplicateKey)
+		}
+	}
+
+	id := uuid.New()
+	if err := r.outbox.add(id, pr.PendingEvents); err != nil {
+		return err
+	}
+	pr.PendingEvents = nil
+
+	now := time.Now()
+	pr.ID = id
+	pr.CreatedAt = now
+	pr.UpdatedAt = now
+	pr.Version = 1
+
+	r.pullRequests[pr.ID] = clonePullRequest(pr)
+	r.appendEvents(pr, now)
+	return nil
+}
+
+func (r *PullRequestRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pr, ok := r.pullRequests[id]
+	if !ok {
+		return nil, repositories.ErrPullRequestNotFound
+	}
+
+	c := clonePullRequest(&pr)
+	return &c, nil
+}
+
+func (r *PullRequestRepository) FindByExternalID(ctx context.Context, externalID string) (*models.PullRequest, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, pr := range r.pullRequests {
+		if pr.ExternalID == externalID {
+			c := clonePullRequest(&pr)
+			return &c, nil
+		}
+	}
+
+	return nil, repositories.ErrPullRequestNotFound
+}
+
+func (r *PullRequestRepository) FindAll(ctx context.Context) ([]*models.PullRequest, error) {
+	return r.filter(func(*models.PullRequest) bool { return true }), nil
+}
+
+func (r *PullRequestRepository) Update(ctx context.Context, pr *models.PullRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.pullRequests[pr.ID]
+	if !ok {
+		return repositories.ErrPullRequestNotFound
+	}
+	if existing.Version != pr.Version {
+		return fmt.Errorf("update pull request %s: %w", pr.ID, repositories.ErrConcurrentModification)
+	}
+
+	if err := r.outbox.add(pr.ID, pr.PendingEvents); err != nil {
+		return err
+	}
+	pr.PendingEvents = nil
+
+	existing.Title = pr.Title
+	existing.AuthorID = pr.AuthorID
+	existing.StatusID = pr.StatusID
+	existing.MergedAt = pr.MergedAt
+	existing.ReviewersIDs = cloneIDs(pr.ReviewersIDs)
+	existing.Reviews = cloneReviews(pr.Reviews)
+	existing.UpdatedAt = time.Now()
+	existing.Version++
+	r.pullRequests[pr.ID] = existing
+
+	pr.UpdatedAt = existing.UpdatedAt
+	pr.Version = existing.Version
+	r.appendEvents(pr, existing.UpdatedAt)
+	return nil
+}
+
+func (r *PullRequestRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.pullRequests[id]; !ok {
+		return repositories.ErrPullRequestNotFound
+	}
+	delete(r.pullRequests, id)
+	delete(r.events, id)
+	return nil
+}
+
+func (r *PullRequestRepository) FindByAuthor(ctx context.Context, authorID uuid.UUID) ([]*models.PullRequest, error) {
+	return r.filter(func(pr *models.PullRequest) bool { return pr.AuthorID == authorID }), nil
+}
+
+func (r *PullRequestRepository) FindByReviewer(ctx context.Context, reviewerID uuid.UUID, filter repositories.PullRequestFilter) ([]*models.PullRequest, error) {
+	filter.ReviewerID = &reviewerID
+	return r.List(ctx, filter)
+}
+
+func (r *PullRequestRepository) List(ctx context.Context, filter repositories.PullRequestFilter) ([]*models.PullRequest, error) {
+	statusIDs := make(map[uuid.UUID]bool, len(filter.Statuses))
+	for _, name := range filter.Statuses {
+		if id, ok := r.statuses.idByName(name); ok {
+			statusIDs[id] = true
+		}
+	}
+	if len(filter.Statuses) > 0 && len(statusIDs) == 0 {
+		return nil, nil
+	}
+
+	list := r.filter(func(pr *models.PullRequest) bool {
+		switch {
+		case len(statusIDs) > 0 && !statusIDs[pr.StatusID]:
+			return false
+		case len(filter.AuthorIDs) > 0 && !slices.Contains(filter.AuthorIDs, pr.AuthorID):
+			return false
+		case filter.ReviewerID != nil && !slices.Contains(pr.ReviewersIDs, *filter.ReviewerID):
+			return false
+		case !inRange(&pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo):
+			return false
+		case (filter.MergedFrom != nil || filter.MergedTo != nil) && !inRange(pr.MergedAt, filter.MergedFrom, filter.MergedTo):
+			return false
+		case filter.After != nil && !before(pr, filter.After):
+			return false
+		}
+		return true
+	})
+
+	if filter.Limit > 0 && len(list) > filter.Limit {
+		list = list[:filter.Limit]
+	}
+	return list, nil
+}
+
+func (r *PullRequestRepository) CountOpenReviews(ctx context.Context, reviewerIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(reviewerIDs))
+	if len(reviewerIDs) == 0 {
+		return counts, nil
+	}
+
+	openID, ok := r.statuses.idByName(models.StatusOpen)
+	if !ok {
+		return counts, nil
+	}
+
+	wanted := make(map[uuid.UUID]bool, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		wanted[id] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, pr := range r.pullRequests {
+		if pr.StatusID != openID {
+			continue
+		}
+		for _, rid := range pr.ReviewersIDs {
+			if wanted[rid] {
+				counts[rid]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *PullRequestRepository) FindAssignmentEvents(ctx context.Context, prID uuid.UUID) ([]*models.AssignmentEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]*models.AssignmentEvent, 0, len(r.events[prID]))
+	for _, e := range r.events[prID] {
+		c := e
+		events = append(events, &c)
+	}
+	return events, nil
+}
+
+// appendEvents must be called with the write lock held.
+func (r *PullRequestRepository) appendEvents(pr *models.PullRequest, at time.Time) {
+	for i := range pr.AssignmentEvents {
+		r.lastEventID++
+		e := &pr.AssignmentEvents[i]
+		e.ID = r.lastEventID
+		e.PullRequestID = pr.ID
+		e.CreatedAt = at
+		r.events[pr.ID] = append(r.events[pr.ID], *e)
+	}
+	pr.AssignmentEvents = nil
+}
+
+func (r *PullRequestRepository) filter(keep func(*models.PullRequest) bool) []*models.PullRequest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var list []*models.PullRequest
+	for _, pr := range r.pullRequests {
+		if !keep(&pr) {
+			continue
+		}
+		c := clonePullRequest(&pr)
+		list = append(list, &c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].ID.String() > list[j].ID.String()
+	})
+
+	return list
+}
+
+// before reports whether pr sorts after the cursor in newest-first order.
+func before(pr *models.PullRequest, cursor *repositories.PullRequestCursor) bool {
+	if !pr.CreatedAt.Equal(cursor.CreatedAt) {
+		return pr.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return pr.ID.String() < cursor.ID.String()
+}
+
+func inRange(t *time.Time, from *time.Time, to *time.Time) bool {
+	if t == nil {
+		return false
+	}
+	if from != nil && t.Before(*from) {
+		return false
+	}
+	return to == nil || t.Before(*to)
+}
+
+func clonePullRequest(pr *models.PullRequest) models.PullRequest {
+	c := *pr
+	c.ReviewersIDs = cloneIDs(pr.ReviewersIDs)
+	c.Reviews = cloneReviews(pr.Reviews)
+	c.AssignmentEvents = nil
+	c.PendingEvents = nil
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		c.MergedAt = &mergedAt
+	}
+	return c
+}
+
+func cloneReviews(reviews map[uuid.UUID]models.Review) map[uuid.UUID]models.Review {
+	if reviews == nil {
+		return nil
+	}
+	c := make(map[uuid.UUID]models.Review, len(reviews))
+	for id, review := range reviews {
+		if review.VerdictAt != nil {
+			at := *review.VerdictAt
+			review.VerdictAt = &at
+		}
+		c[id] = review
+	}
+	return c
+}
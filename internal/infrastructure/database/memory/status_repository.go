@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type StatusRepository struct {
+	mu       sync.RWMutex
+	statuses map[uuid.UUID]models.Status
+}
+
+func NewStatusRepository() *StatusRepository {
+	r := &StatusRepository{statuses: make(map[uuid.UUID]models.Status)}
+	for _, name := range models.StatusNames {
+		id := uuid.New()
+		r.statuses[id] = models.Status{ID: id, Name: string(name)}
+	}
+	return r
+}
+
+func (r *StatusRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Status, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.statuses[id]
+	if !ok {
+		return nil, repositories.ErrStatusNotFound
+	}
+	return &s, nil
+}
+
+func (r *StatusRepository) FindAll(ctx context.Context) ([]*models.Status, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]*models.Status, 0, len(r.statuses))
+	for _, s := range r.statuses {
+		statuses = append(statuses, &s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses, nil
+}
+
+func (r *StatusRepository) idByName(name models.StatusName) (uuid.UUID, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for id, s := range r.statuses {
+		if s.StatusName() == name {
+			return id, true
+		}
+	}
+	return uuid.Nil, false
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeamRepository struct {
+	mu    sync.RWMutex
+	teams map[uuid.UUID]models.Team
+}
+
+func NewTeamRepository() *TeamRepository {
+	return &TeamRepository{teams: make(map[uuid.UUID]models.Team)}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.findByName(team.Name); ok {
+		return fmt.Errorf("create team: name %q: %w", team.Name, ErrDuplicateKey)
+	}
+
+	now := time.Now()
+	team.ID = uuid.New()
+	team.CreatedAt = now
+	team.UpdatedAt = now
+	team.Version = 1
+
+	r.teams[team.ID] = cloneTeam(team)
+	return nil
+}
+
+func (r *TeamRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.teams[id]
+	if !ok {
+		return nil, repositories.ErrTeamNotFound
+	}
+
+	c := cloneTeam(&t)
+	return &c, nil
+}
+
+func (r *TeamRepository) FindAll(ctx context.Context) ([]*models.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	teams := make([]*models.Team, 0, len(r.teams))
+	for _, t := range r.teams {
+		c := cloneTeam(&t)
+		teams = append(teams, &c)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].CreatedAt.After(teams[j].CreatedAt)
+	})
+
+	return teams, nil
+}
+
+func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.teams[team.ID]
+	if !ok {
+		return repositories.ErrTeamNotFound
+	}
+	if existing.Version != team.Version {
+		return fmt.Errorf("update team %s: %w", team.ID, repositories.ErrConcurrentModification)
+	}
+	if other, ok := r.findByName(team.Name); ok && other.ID != team.ID {
+		return fmt.Errorf("update team %s: name %q: %w", team.ID, team.Name, ErrDuplicateKey)
+	}
+
+	existing.Name = team.Name
+	existing.UserIDs = cloneIDs(team.UserIDs)
+	existing.UpdatedAt = time.Now()
+	existing.Version++
+	r.teams[team.ID] = existing
+
+	team.UpdatedAt = existing.UpdatedAt
+	team.Version = existing.Version
+	return nil
+}
+
+func (r *TeamRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.teams[id]; !ok {
+		return repositories.ErrTeamNotFound
+	}
+	delete(r.teams, id)
+	return nil
+}
+
+func (r *TeamRepository) FindByName(ctx context.Context, name string) (*models.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.findByName(name)
+	if !ok {
+		return nil, repositories.ErrTeamNotFound
+	}
+
+	c := cloneTeam(&t)
+	return &c, nil
+}
+
+func (r *TeamRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*models.Team, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, t := range r.teams {
+		for _, uid := range t.UserIDs {
+			if uid == userID {
+				c := cloneTeam(&t)
+				return &c, nil
+			}
+		}
+	}
+
+	return nil, repositories.ErrTeamNotFound
+}
+
+func (r *TeamRepository) findByName(name string) (models.Team, bool) {
+	for _, t := range r.teams {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return models.Team{}, false
+}
+
+func cloneTeam(t *models.Team) models.Team {
+	c := *t
+	c.UserIDs = cloneIDs(t.UserIDs)
+	if c.UserIDs == nil {
+		c.UserIDs = []uuid.UUID{}
+	}
+	return c
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeamSettingsRepository struct {
+	mu       sync.RWMutex
+	settings map[uuid.UUID]models.TeamSettings
+}
+
+func NewTeamSettingsRepository() *TeamSettingsRepository {
+	return &TeamSettingsRepository{settings: make(map[uuid.UUID]models.TeamSettings)}
+}
+
+func (r *TeamSettingsRepository) FindByTeamID(ctx context.Context, teamID uuid.UUID) (*models.TeamSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.settings[teamID]
+	if !ok {
+		return nil, repositories.ErrTeamSettingsNotFound
+	}
+	return &s, nil
+}
+
+func (r *TeamSettingsRepository) Upsert(ctx context.Context, settings *models.TeamSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := r.settings[settings.TeamID]; ok {
+		settings.CreatedAt = existing.CreatedAt
+	} else {
+		settings.CreatedAt = now
+	}
+	settings.UpdatedAt = now
+
+	r.settings[settings.TeamID] = *settings
+	return nil
+}
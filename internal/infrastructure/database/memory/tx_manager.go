@@ -0,0 +1,16 @@
+package memory
+
+import "context"
+
+// TxManager runs fn directly. Memory storage has no rollback, so a unit of
+// work that fails halfway keeps its earlier writes; use postgres storage where
+// atomicity matters.
+type TxManager struct{}
+
+func NewTxManager() *TxManager {
+	return &TxManager{}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UnavailabilityRepository struct {
+	mu      sync.RWMutex
+	periods map[uuid.UUID]models.UserUnavailability
+}
+
+func NewUnavailabilityRepository() *UnavailabilityRepository {
+	return &UnavailabilityRepository{periods: make(map[uuid.UUID]models.UserUnavailability)}
+}
+
+func (r *UnavailabilityRepository) Create(ctx context.Context, period *models.UserUnavailability) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	period.ID = uuid.New()
+	period.CreatedAt = time.Now()
+
+	r.periods[period.ID] = cloneUnavailability(period)
+	return nil
+}
+
+func (r *UnavailabilityRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserUnavailability, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var list []*models.UserUnavailability
+	for _, p := range r.periods {
+		if p.UserID == userID {
+			c := cloneUnavailability(&p)
+			list = append(list, &c)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartsAt.Equal(list[j].StartsAt) {
+			return list[i].StartsAt.Before(list[j].StartsAt)
+		}
+		return list[i].ID.String() < list[j].ID.String()
+	})
+	return list, nil
+}
+
+func (r *UnavailabilityRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.periods[id]; !ok {
+		return repositories.ErrUnavailabilityNotFound
+	}
+	delete(r.periods, id)
+	return nil
+}
+
+func (r *UnavailabilityRepository) DeleteBySourceID(ctx context.Context, sourceID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, p := range r.periods {
+		if p.SourceID != nil && *p.SourceID == sourceID {
+			delete(r.periods, id)
+		}
+	}
+	return nil
+}
+
+func (r *UnavailabilityRepository) FindUnavailableUserIDs(ctx context.Context, userIDs []uuid.UUID, at time.Time) (map[uuid.UUID]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	unavailable := make(map[uuid.UUID]bool)
+	for _, p := range r.periods {
+		if wanted[p.UserID] && p.Covers(at) {
+			unavailable[p.UserID] = true
+		}
+	}
+	return unavailable, nil
+}
+
+func cloneUnavailability(p *models.UserUnavailability) models.UserUnavailability {
+	c := *p
+	if p.SourceID != nil {
+		sourceID := *p.SourceID
+		c.SourceID = &sourceID
+	}
+	return c
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type identityKey struct {
+	provider string
+	login    string
+}
+
+type UserIdentityRepository struct {
+	mu         sync.RWMutex
+	identities map[identityKey]models.UserIdentity
+}
+
+func NewUserIdentityRepository() *UserIdentityRepository {
+	return &UserIdentityRepository{identities: make(map[identityKey]models.UserIdentity)}
+}
+
+func (r *UserIdentityRepository) FindByLogin(ctx context.Context, provider string, login string) (*models.UserIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.identities[identityKey{provider, login}]
+	if !ok {
+		return nil, repositories.ErrUserIdentityNotFound
+	}
+	return &id, nil
+}
+
+func (r *UserIdentityRepository) FindByUserID(ctx context.Context, provider string, userID uuid.UUID) (*models.UserIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *models.UserIdentity
+	for _, id := range r.identities {
+		if id.Provider != provider || id.UserID != userID {
+			continue
+		}
+		if found == nil || id.CreatedAt.After(found.CreatedAt) {
+			c := id
+			found = &c
+		}
+	}
+	if found == nil {
+		return nil, repositories.ErrUserIdentityNotFound
+	}
+	return found, nil
+}
+
+func (r *UserIdentityRepository) Upsert(ctx context.Context, identity *models.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := identityKey{identity.Provider, identity.Login}
+	if existing, ok := r.identities[key]; ok {
+		identity.CreatedAt = existing.CreatedAt
+	} else {
+		identity.CreatedAt = time.Now()
+	}
+
+	r.identities[key] = *identity
+	return nil
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[uuid.UUID]models.User
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: make(map[uuid.UUID]models.User)}
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.usernameTaken(user.Username, uuid.Nil) {
+		return fmt.Errorf("create user: username %q: %w", user.Username, ErrDuplicateKey)
+	}
+	if _, ok := r.findByExternalID(user.ExternalID); ok {
+		return fmt.Errorf("create user: external id %q: %w", user.ExternalID, ErrDuplicateKey)
+	}
+
+	now := time.Now()
+	user.ID = uuid.New()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	r.users[user.ID] = cloneUser(user)
+	return nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	c := cloneUser(&u)
+	return &c, nil
+}
+
+func (r *UserRepository) FindByExternalID(ctx context.Context, externalID string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.findByExternalID(externalID)
+	if !ok {
+		return nil, repositories.ErrUserNotFound
+	}
+
+	c := cloneUser(&u)
+	return &c, nil
+}
+
+func (r *UserRepository) FindAll(ctx context.Context) ([]*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*models.User, 0, len(r.users))
+	for _, u := range r.users {
+		c := cloneUser(&u)
+		list = append(list, &c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+
+	return list, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.users[user.ID]
+	if !ok {
+		return repositories.ErrUserNotFound
+	}
+	if r.usernameTaken(user.Username, user.ID) {
+		return fmt.Errorf("update user %s: username %q: %w", user.ID, user.Username, ErrDuplicateKey)
+	}
+
+	existing.Username = user.Username
+	existing.IsActive = user.IsActive
+	existing.UpdatedAt = time.Now()
+	r.users[user.ID] = existing
+
+	user.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+func (r *UserRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return repositories.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+func (r *UserRepository) findByExternalID(externalID string) (models.User, bool) {
+	for _, u := range r.users {
+		if u.ExternalID == externalID {
+			return u, true
+		}
+	}
+	return models.User{}, false
+}
+
+func (r *UserRepository) usernameTaken(username string, except uuid.UUID) bool {
+	for id, u := range r.users {
+		if id != except && u.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneUser(u *models.User) models.User {
+	c := *u
+	c.TeamIDs = cloneIDs(u.TeamIDs)
+	return c
+}
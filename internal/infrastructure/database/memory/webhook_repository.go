@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscriptionRepository struct {
+	mu   sync.RWMutex
+	subs map[uuid.UUID]models.WebhookSubscription
+}
+
+func NewWebhookSubscriptionRepository() *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{subs: make(map[uuid.UUID]models.WebhookSubscription)}
+}
+
+func (r *WebhookSubscriptionRepository) Create(ctx context.Context, sub *models.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	sub.ID = uuid.New()
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+
+	r.subs[sub.ID] = cloneWebhookSubscription(sub)
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) FindAll(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return r.filter(func(*models.WebhookSubscription) bool { return true }), nil
+}
+
+func (r *WebhookSubscriptionRepository) FindByEventType(ctx context.Context, eventType string) ([]*models.WebhookSubscription, error) {
+	return r.filter(func(s *models.WebhookSubscription) bool { return s.Wants(eventType) }), nil
+}
+
+func (r *WebhookSubscriptionRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subs[id]; !ok {
+		return repositories.ErrWebhookNotFound
+	}
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) filter(keep func(*models.WebhookSubscription) bool) []*models.WebhookSubscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var list []*models.WebhookSubscription
+	for _, s := range r.subs {
+		if !keep(&s) {
+			continue
+		}
+		c := cloneWebhookSubscription(&s)
+		list = append(list, &c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+
+	return list
+}
+
+func cloneWebhookSubscription(s *models.WebhookSubscription) models.WebhookSubscription {
+	c := *s
+	c.EventTypes = append([]string(nil), s.EventTypes...)
+	return c
+}
+
+type WebhookDeadLetterRepository struct {
+	mu          sync.Mutex
+	deadLetters []models.WebhookDeadLetter
+}
+
+func NewWebhookDeadLetterRepository() *WebhookDeadLetterRepository {
+	return &WebhookDeadLetterRepository{}
+}
+
+func (r *WebhookDeadLetterRepository) Create(ctx context.Context, dl *models.WebhookDeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dl.ID = uuid.New()
+	dl.CreatedAt = time.Now()
+
+	c := *dl
+	c.Payload = append([]byte(nil), dl.Payload...)
+	r.deadLetters = append(r.deadLetters, c)
+	return nil
+}
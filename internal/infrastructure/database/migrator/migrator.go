@@ -0,0 +1,343 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrDirty            = errors.New("database is in a dirty state, fix it manually and force the version")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+	ErrMissingMigration = errors.New("missing migration file")
+)
+
+// advisoryLockID is shared by every replica, so only one of them migrates at a time.
+const advisoryLockID int64 = 7_351_942_001
+
+const (
+	createVersionTableQuery = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT  NOT NULL PRIMARY KEY,
+			dirty   BOOLEAN NOT NULL
+		);
+	`
+	selectVersionQuery  = `SELECT version, dirty FROM schema_migrations LIMIT 1;`
+	deleteVersionQuery  = `DELETE FROM schema_migrations;`
+	insertVersionQuery  = `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2);`
+	advisoryLockQuery   = `SELECT pg_advisory_lock($1);`
+	advisoryUnlockQuery = `SELECT pg_advisory_unlock($1);`
+)
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version    uint64
+	Dirty      bool
+	Migrations []Migration
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func New(pool *pgxpool.Pool, fsys fs.FS, dir string) (*Migrator, error) {
+	migrations, err := load(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.Goto(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		idx := m.indexOf(current)
+		if current != 0 && idx < 0 {
+			return fmt.Errorf("%w: %d", ErrUnknownVersion, current)
+		}
+
+		target := uint64(0)
+		if idx-steps >= 0 {
+			target = m.migrations[idx-steps].Version
+		}
+
+		return m.migrateTo(ctx, conn, current, target)
+	})
+}
+
+func (m *Migrator) Goto(ctx context.Context, target uint64) error {
+	if target != 0 && m.indexOf(target) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.migrateTo(ctx, conn, current, target)
+	})
+}
+
+// Force records version as the current, clean one without running any
+// migration. It is how an operator clears the dirty state after repairing
+// the schema by hand.
+func (m *Migrator) Force(ctx context.Context, version uint64) error {
+	if version != 0 && m.indexOf(version) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return setVersion(ctx, conn, version, false)
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	var st Status
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err := readVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		st.Version = version
+		st.Dirty = dirty
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	st.Migrations = m.migrations
+	return &st, nil
+}
+
+func (m *Migrator) migrateTo(ctx context.Context, conn *pgxpool.Conn, current, target uint64) error {
+	if current != 0 && m.indexOf(current) < 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, current)
+	}
+
+	for _, mig := range m.migrations {
+		if mig.Version <= current || mig.Version > target {
+			continue
+		}
+		if mig.Up == "" {
+			return fmt.Errorf("%w: %d up", ErrMissingMigration, mig.Version)
+		}
+		if err := m.apply(ctx, conn, mig.Up, mig.Version); err != nil {
+			return fmt.Errorf("apply migration %d_%s up: %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mig := m.migrations[i]
+		if mig.Version > current || mig.Version <= target {
+			continue
+		}
+		if mig.Down == "" {
+			return fmt.Errorf("%w: %d down", ErrMissingMigration, mig.Version)
+		}
+
+		prev := uint64(0)
+		if i > 0 {
+			prev = m.migrations[i-1].Version
+		}
+		if err := m.apply(ctx, conn, mig.Down, prev); err != nil {
+			return fmt.Errorf("apply migration %d_%s down: %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// apply marks newVersion dirty before running sql, then runs sql and records
+// newVersion as clean in one transaction. If the migration fails, the mark
+// stays behind and later runs refuse to migrate until the version is forced.
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, sql string, newVersion uint64) error {
+	if err := setVersion(ctx, conn, newVersion, true); err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	if err := writeVersion(ctx, tx, newVersion, false); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) currentVersion(ctx context.Context, conn *pgxpool.Conn) (uint64, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w: version %d", ErrDirty, version)
+	}
+	return version, nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, advisoryLockQuery, advisoryLockID); err != nil {
+		return fmt.Errorf("acquire advisory lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), advisoryUnlockQuery, advisoryLockID)
+
+	if _, err := conn.Exec(ctx, createVersionTableQuery); err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) indexOf(version uint64) int {
+	for i, mig := range m.migrations {
+		if mig.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+func readVersion(ctx context.Context, conn *pgxpool.Conn) (uint64, bool, error) {
+	var version int64
+	var dirty bool
+
+	err := conn.QueryRow(ctx, selectVersionQuery).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+
+	return uint64(version), dirty, nil
+}
+
+// setVersion writes the version in a transaction of its own.
+func setVersion(ctx context.Context, conn *pgxpool.Conn, version uint64, dirty bool) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := writeVersion(ctx, tx, version, dirty); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit schema version: %w", err)
+	}
+	return nil
+}
+
+// writeVersion replaces the single version row. A clean version 0 is stored
+// as no row at all.
+func writeVersion(ctx context.Context, tx pgx.Tx, version uint64, dirty bool) error {
+	if _, err := tx.Exec(ctx, deleteVersionQuery); err != nil {
+		return fmt.Errorf("clear schema version: %w", err)
+	}
+	if version == 0 && !dirty {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, insertVersionQuery, int64(version), dirty); err != nil {
+		return fmt.Errorf("write schema version %d: %w", version, err)
+	}
+	return nil
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse version of %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		}
+		if match[3] == "up" {
+			mig.Up = string(content)
+		} else {
+			mig.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,95 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"pullrequest-manager/internal/infrastructure/database/migrator"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+// testPool connects to the test database with a schema of its own on the
+// search path, so the migrations here do not touch the real schema.
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	connString := os.Getenv(testDatabaseEnv)
+	if connString == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("migrator_test_%d", time.Now().UnixNano())
+	admin, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE") })
+
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		t.Fatalf("parse %s: %v", testDatabaseEnv, err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestFailedMigrationLeavesDirtyVersion(t *testing.T) {
+	pool := testPool(t)
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"m/001_items.up.sql":    {Data: []byte("CREATE TABLE items (id INT);")},
+		"m/001_items.down.sql":  {Data: []byte("DROP TABLE items;")},
+		"m/002_broken.up.sql":   {Data: []byte("ALTER TABLE missing ADD COLUMN x INT;")},
+		"m/002_broken.down.sql": {Data: []byte("SELECT 1;")},
+	}
+	m, err := migrator.New(pool, fsys, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Up(ctx); err == nil {
+		t.Fatal("Up succeeded with a broken migration")
+	}
+	st, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Version != 2 || !st.Dirty {
+		t.Errorf("status = %d dirty %v, want 2 dirty", st.Version, st.Dirty)
+	}
+	if err := m.Up(ctx); !errors.Is(err, migrator.ErrDirty) {
+		t.Errorf("Up on a dirty database = %v, want ErrDirty", err)
+	}
+
+	// The broken migration rolled back, so 1 is the version to force.
+	if err := m.Force(ctx, 1); err != nil {
+		t.Fatalf("Force: %v", err)
+	}
+	if st, err := m.Status(ctx); err != nil || st.Version != 1 || st.Dirty {
+		t.Errorf("status after Force = %+v (%v), want 1 clean", st, err)
+	}
+	if err := m.Down(ctx, 1); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if st, err := m.Status(ctx); err != nil || st.Version != 0 || st.Dirty {
+		t.Errorf("status after Down = %+v (%v), want 0 clean", st, err)
+	}
+}
@@ -0,0 +1,142 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CalendarSourceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCalendarSourceRepository(db *pgxpool.Pool) *CalendarSourceRepository {
+	return &CalendarSourceRepository{db: db}
+}
+
+const (
+	upsertCalendarSourceQuery = `
+		INSERT INTO calendar_sources (user_id, url)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, url) DO UPDATE
+		SET url = EXCLUDED.url
+		RETURNING id, last_synced_at, last_error, created_at, updated_at;
+	`
+	selectCalendarSourceByIDQuery = `
+		SELECT id, user_id, url, last_synced_at, last_error, created_at, updated_at
+		FROM calendar_sources
+		WHERE id = $1;
+	`
+	selectCalendarSourcesByUserQuery = `
+		SELECT id, user_id, url, last_synced_at, last_error, created_at, updated_at
+		FROM calendar_sources
+		WHERE user_id = $1
+		ORDER BY created_at, id;
+	`
+	selectCalendarSourcesWithURLQuery = `
+		SELECT id, user_id, url, last_synced_at, last_error, created_at, updated_at
+		FROM calendar_sources
+		WHERE url <> ''
+		ORDER BY last_synced_at NULLS FIRST, id;
+	`
+	updateCalendarSourceQuery = `
+		UPDATE calendar_sources
+		SET last_synced_at = $2, last_error = $3
+		WHERE id = $1
+		RETURNING updated_at;
+	`
+	deleteCalendarSourceQuery = `
+		DELETE FROM calendar_sources WHERE id = $1;
+	`
+)
+
+func (r *CalendarSourceRepository) Upsert(ctx context.Context, source *models.CalendarSource) error {
+	if err := conn(ctx, r.db).QueryRow(
+		ctx,
+		upsertCalendarSourceQuery,
+		source.UserID,
+		source.URL,
+	).Scan(&source.ID, &source.LastSyncedAt, &source.LastError, &source.CreatedAt, &source.UpdatedAt); err != nil {
+		return fmt.Errorf("upsert calendar source for user %s: %w", source.UserID, err)
+	}
+	return nil
+}
+
+func (r *CalendarSourceRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.CalendarSource, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, selectCalendarSourceByIDQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("find calendar source %s: %w", id, err)
+	}
+
+	source, err := pgx.CollectExactlyOneRow(rows, scanCalendarSource)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repositories.ErrCalendarSourceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan calendar source %s: %w", id, err)
+	}
+	return source, nil
+}
+
+func (r *CalendarSourceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.CalendarSource, error) {
+	return r.query(ctx, selectCalendarSourcesByUserQuery, userID)
+}
+
+func (r *CalendarSourceRepository) FindWithURL(ctx context.Context) ([]*models.CalendarSource, error) {
+	return r.query(ctx, selectCalendarSourcesWithURLQuery)
+}
+
+func (r *CalendarSourceRepository) Update(ctx context.Context, source *models.CalendarSource) error {
+	err := conn(ctx, r.db).QueryRow(
+		ctx,
+		updateCalendarSourceQuery,
+		source.ID,
+		source.LastSyncedAt,
+		source.LastError,
+	).Scan(&source.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repositories.ErrCalendarSourceNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update calendar source %s: %w", source.ID, err)
+	}
+	return nil
+}
+
+func (r *CalendarSourceRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.db).Exec(ctx, deleteCalendarSourceQuery, id)
+	if err != nil {
+		return fmt.Errorf("delete calendar source %s: %w", id, err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return repositories.ErrCalendarSourceNotFound
+	}
+
+	return nil
+}
+
+func (r *CalendarSourceRepository) query(ctx context.Context, query string, args ...any) ([]*models.CalendarSource, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("find calendar sources: %w", err)
+	}
+
+	sources, err := pgx.CollectRows(rows, scanCalendarSource)
+	if err != nil {
+		return nil, fmt.Errorf("scan calendar sources: %w", err)
+	}
+	return sources, nil
+}
+
+func scanCalendarSource(row pgx.CollectableRow) (*models.CalendarSource, error) {
+	var s models.CalendarSource
+	err := row.Scan(&s.ID, &s.UserID, &s.URL, &s.LastSyncedAt, &s.LastError, &s.CreatedAt, &s.UpdatedAt)
+	return &s, err
+}
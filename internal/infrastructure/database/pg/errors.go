@@ -0,0 +1,21 @@
+package pg
+
+import (
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const uniqueViolation = "23505"
+
+// duplicateKey marks unique violations with repositories.ErrDuplicateKey so
+// callers can tell them apart from other database errors.
+func duplicateKey(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", repositories.ErrDuplicateKey, pgErr.ConstraintName)
+	}
+	return err
+}
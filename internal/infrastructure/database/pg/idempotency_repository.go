@@ -0,0 +1,124 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(db *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+const (
+	reserveIdempotencyKeyQuery = `
+		INSERT INTO idempotency_keys (scope, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			response_headers = '{}',
+			response_body = NULL,
+			created_at = now(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= now()
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $5)
+		RETURNING created_at;
+	`
+	selectIdempotencyKeyQuery = `
+		SELECT scope, key, request_hash, status_code, response_headers, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2;
+	`
+	completeIdempotencyKeyQuery = `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_headers = $4, response_body = $5
+		WHERE scope = $1 AND key = $2;
+	`
+	deleteIdempotencyKeyQuery = `
+		DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status_code IS NULL;
+	`
+	deleteExpiredIdempotencyKeysQuery = `
+		DELETE FROM idempotency_keys WHERE expires_at <= $1;
+	`
+)
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, rec *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	err := conn(ctx, r.db).QueryRow(
+		ctx,
+		reserveIdempotencyKeyQuery,
+		rec.Scope,
+		rec.Key,
+		rec.RequestHash,
+		rec.ExpiresAt,
+		staleBefore,
+	).Scan(&rec.CreatedAt)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("reserve idempotency key %s %q: %w", rec.Scope, rec.Key, err)
+	}
+
+	var (
+		existing models.IdempotencyRecord
+		status   *int
+	)
+	err = conn(ctx, r.db).QueryRow(ctx, selectIdempotencyKeyQuery, rec.Scope, rec.Key).Scan(
+		&existing.Scope,
+		&existing.Key,
+		&existing.RequestHash,
+		&status,
+		&existing.ResponseHeaders,
+		&existing.ResponseBody,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The holder released the key in between; let the caller retry.
+		return r.Reserve(ctx, rec, staleBefore)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find idempotency key %s %q: %w", rec.Scope, rec.Key, err)
+	}
+	if status != nil {
+		existing.StatusCode = *status
+	}
+	return &existing, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, rec *models.IdempotencyRecord) error {
+	headers := rec.ResponseHeaders
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	_, err := conn(ctx, r.db).Exec(ctx, completeIdempotencyKeyQuery, rec.Scope, rec.Key, rec.StatusCode, headers, rec.ResponseBody)
+	if err != nil {
+		return fmt.Errorf("complete idempotency key %s %q: %w", rec.Scope, rec.Key, err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, scope string, key string) error {
+	if _, err := conn(ctx, r.db).Exec(ctx, deleteIdempotencyKeyQuery, scope, key); err != nil {
+		return fmt.Errorf("release idempotency key %s %q: %w", scope, key, err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	cmd, err := conn(ctx, r.db).Exec(ctx, deleteExpiredIdempotencyKeysQuery, now)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+	return cmd.RowsAffected(), nil
+}
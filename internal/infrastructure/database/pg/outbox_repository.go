@@ -0,0 +1,153 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+const (
+	insertOutboxEventQuery = `
+		INSERT INTO outbox_events (id, aggregate_id, event_type, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5);
+	`
+	// claimOutboxEventsQuery leases due events in one short statement. An
+	// event whose aggregate has an earlier event that is leased or waiting for
+	// a retry stays behind it.
+	claimOutboxEventsQuery = `
+		UPDATE outbox_events
+		SET next_attempt_at = now() + $3::interval
+		WHERE id IN (
+			SELECT e.id
+			FROM outbox_events e
+			WHERE e.published_at IS NULL AND e.attempts < $2 AND e.next_attempt_at <= now()
+				AND NOT EXISTS (
+					SELECT 1
+					FROM outbox_events earlier
+					WHERE earlier.aggregate_id = e.aggregate_id
+						AND earlier.published_at IS NULL
+						AND earlier.attempts < $2
+						AND earlier.next_attempt_at > now()
+						AND earlier.occurred_at < e.occurred_at
+				)
+			ORDER BY e.occurred_at
+			LIMIT $1
+			FOR UPDATE OF e SKIP LOCKED
+		)
+		RETURNING id, COALESCE(aggregate_id, id), event_type, payload, occurred_at, attempts, last_error, next_attempt_at;
+	`
+	markOutboxEventPublishedQuery = `
+		UPDATE outbox_events SET published_at = now(), attempts = attempts + 1, last_error = ''
+		WHERE id = $1;
+	`
+	markOutboxEventFailedQuery = `
+		UPDATE outbox_events SET attempts = attempts + 1, last_error = $2, next_attempt_at = now() + $3::interval
+		WHERE id = $1;
+	`
+	releaseOutboxEventsQuery = `
+		UPDATE outbox_events SET next_attempt_at = now()
+		WHERE id = ANY($1) AND published_at IS NULL;
+	`
+)
+
+// releaseTimeout bounds giving back leases once the relay is stopping.
+const releaseTimeout = 5 * time.Second
+
+func (r *OutboxRepository) Process(
+	ctx context.Context,
+	limit int,
+	maxAttempts int,
+	lease time.Duration,
+	retryDelay func(attempts int) time.Duration,
+	handle func(ctx context.Context, event *models.OutboxEvent) error,
+) (published int, err error) {
+	rows, err := conn(ctx, r.db).Query(ctx, claimOutboxEventsQuery, limit, maxAttempts, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox events: %w", err)
+	}
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.OutboxEvent, error) {
+		var e models.OutboxEvent
+		err := row.Scan(&e.ID, &e.AggregateID, &e.EventType, &e.Payload, &e.OccurredAt, &e.Attempts, &e.LastError, &e.NextAttemptAt)
+		return &e, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("scan outbox events: %w", err)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+
+	// Events that are not handled in this pass get their lease back, so they
+	// do not wait for it to run out.
+	var unhandled []uuid.UUID
+	defer func() {
+		if len(unhandled) == 0 {
+			return
+		}
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancel()
+		if _, releaseErr := conn(releaseCtx, r.db).Exec(releaseCtx, releaseOutboxEventsQuery, unhandled); releaseErr != nil {
+			err = errors.Join(err, fmt.Errorf("release outbox events: %w", releaseErr))
+		}
+	}()
+
+	failed := make(map[uuid.UUID]bool)
+	for i, e := range events {
+		if ctx.Err() != nil {
+			unhandled = appendIDs(unhandled, events[i:])
+			return published, ctx.Err()
+		}
+		// A failed event holds back the rest of its aggregate only.
+		if failed[e.AggregateID] {
+			unhandled = append(unhandled, e.ID)
+			continue
+		}
+		if handleErr := handle(ctx, e); handleErr != nil {
+			failed[e.AggregateID] = true
+			delay := retryDelay(e.Attempts + 1)
+			if _, err := conn(ctx, r.db).Exec(ctx, markOutboxEventFailedQuery, e.ID, handleErr.Error(), delay); err != nil {
+				unhandled = appendIDs(unhandled, events[i+1:])
+				return published, fmt.Errorf("record failure of outbox event %s: %w", e.ID, err)
+			}
+			continue
+		}
+		if _, err := conn(ctx, r.db).Exec(ctx, markOutboxEventPublishedQuery, e.ID); err != nil {
+			unhandled = appendIDs(unhandled, events[i+1:])
+			return published, fmt.Errorf("mark outbox event %s published: %w", e.ID, err)
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+func appendIDs(ids []uuid.UUID, events []*models.OutboxEvent) []uuid.UUID {
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func insertOutboxEventsTx(ctx context.Context, tx pgx.Tx, aggregateID uuid.UUID, events []models.OutboxEvent) error {
+	for _, e := range events {
+		if _, err := tx.Exec(ctx, insertOutboxEventQuery, e.ID, aggregateID, e.EventType, e.Payload, e.OccurredAt); err != nil {
+			return fmt.Errorf("insert outbox event %s: %w", e.EventType, err)
+		}
+	}
+	return nil
+}
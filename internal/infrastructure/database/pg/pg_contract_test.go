@@ -0,0 +1,20 @@
+package pg_test
+
+import (
+	"pullrequest-manager/internal/infrastructure/database/pg"
+	"pullrequest-manager/internal/infrastructure/database/repotest"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		pool := testPool(t, nil)
+		return repotest.Repositories{
+			Users:        pg.NewUserRepository(pool),
+			Teams:        pg.NewTeamRepository(pool),
+			Statuses:     pg.NewStatusRepository(pool),
+			PullRequests: pg.NewPullRequestRepository(pool),
+			Outbox:       pg.NewOutboxRepository(pool),
+		}
+	})
+}
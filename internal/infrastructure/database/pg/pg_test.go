@@ -0,0 +1,82 @@
+package pg_test
+
+import (
+	"context"
+	"os"
+	"pullrequest-manager/database/migrations"
+	"pullrequest-manager/internal/infrastructure/database/migrator"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDatabaseEnv names the database the tests run against. It is separate
+// from DATABASE_URL because every test empties the tables.
+const testDatabaseEnv = "TEST_DATABASE_URL"
+
+const truncateTablesQuery = `
+	DO $$
+	DECLARE t text;
+	BEGIN
+		FOR t IN
+			SELECT tablename FROM pg_tables
+			WHERE schemaname = current_schema()
+				AND tablename NOT IN ('schema_migrations', 'pull_request_statuses')
+		LOOP
+			EXECUTE format('TRUNCATE TABLE %I CASCADE', t);
+		END LOOP;
+	END $$;
+`
+
+// testPool connects to the test database, migrates it to the latest version
+// and empties it. Without the database the test is skipped. A non-nil tracer
+// sees every query sent through the pool.
+func testPool(tb testing.TB, tracer pgx.QueryTracer) *pgxpool.Pool {
+	tb.Helper()
+
+	connString := os.Getenv(testDatabaseEnv)
+	if connString == "" {
+		tb.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	ctx := context.Background()
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		tb.Fatalf("parse %s: %v", testDatabaseEnv, err)
+	}
+	config.ConnConfig.Tracer = tracer
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		tb.Fatalf("connect to test database: %v", err)
+	}
+	tb.Cleanup(pool.Close)
+
+	m, err := migrator.New(pool, migrations.PG, "pg")
+	if err != nil {
+		tb.Fatalf("load migrations: %v", err)
+	}
+	if err := m.Up(ctx); err != nil {
+		tb.Fatalf("migrate test database: %v", err)
+	}
+	if _, err := pool.Exec(ctx, truncateTablesQuery); err != nil {
+		tb.Fatalf("empty test database: %v", err)
+	}
+
+	return pool
+}
+
+// queryCounter is a pgx.QueryTracer counting the queries sent.
+type queryCounter struct {
+	n atomic.Int64
+}
+
+func (c *queryCounter) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	c.n.Add(1)
+	return ctx
+}
+
+func (c *queryCounter) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,7 +14,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrPullRequestNotFound = errors.New("pull request not found")
+var ErrPullRequestNotFound = repositories.ErrPullRequestNotFound
 
 type PullRequestRepository struct {
 	db *pgxpool.Pool
@@ -24,50 +26,107 @@ func NewPullRequestRepository(db *pgxpool.Pool) *PullRequestRepository {
 
 const (
 	insertPullRequestQuery = `
-		INSERT INTO pull_requests (title, author_id, status_id, merged_at)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at;
+		INSERT INTO pull_requests (external_id, title, author_id, status_id, merged_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at, version;
 	`
 	selectPullRequestByIDQuery = `
-		SELECT id, title, author_id, status_id, merged_at, created_at, updated_at
+		SELECT id, external_id, title, author_id, status_id, merged_at, created_at, updated_at, version
 		FROM pull_requests
 		WHERE id = $1;
 	`
+	selectPullRequestByExternalIDQuery = `
+		SELECT id, external_id, title, author_id, status_id, merged_at, created_at, updated_at, version
+		FROM pull_requests
+		WHERE external_id = $1;
+	`
 	selectAllPullRequestsQuery = `
-		SELECT id, title, author_id, status_id, merged_at, created_at, updated_at
+		SELECT id, external_id, title, author_id, status_id, merged_at, created_at, updated_at, version
 		FROM pull_requests
 		ORDER BY created_at DESC;
 	`
 	updatePullRequestQuery = `
 		UPDATE pull_requests
-		SET title = $1, author_id = $2, status_id = $3, merged_at = $4, updated_at = now()
-		WHERE id = $5
-		RETURNING updated_at;
+		SET title = $1, author_id = $2, status_id = $3, merged_at = $4, updated_at = now(), version = version + 1
+		WHERE id = $5 AND version = $6
+		RETURNING updated_at, version;
+	`
+	pullRequestExistsQuery = `
+		SELECT EXISTS (SELECT 1 FROM pull_requests WHERE id = $1);
 	`
 	deletePullRequestQuery = `
 		DELETE FROM pull_requests WHERE id = $1;
 	`
 	selectByAuthorQuery = `
-		SELECT id, title, author_id, status_id, merged_at, created_at, updated_at
+		SELECT id, external_id, title, author_id, status_id, merged_at, created_at, updated_at, version
 		FROM pull_requests
 		WHERE author_id = $1
 		ORDER BY created_at DESC;
 	`
+	listPullRequestsQuery = `
+		SELECT pr.id, pr.external_id, pr.title, pr.author_id, pr.status_id, pr.merged_at, pr.created_at, pr.updated_at, pr.version
+		FROM pull_requests pr
+		JOIN pull_request_statuses s ON s.id = pr.status_id
+		WHERE (cardinality($1::text[]) = 0 OR s.name = ANY($1))
+			AND (cardinality($2::uuid[]) = 0 OR pr.author_id = ANY($2))
+			AND ($3::uuid IS NULL OR EXISTS (
+				SELECT 1 FROM pull_request_reviewers prr
+				WHERE prr.pull_request_id = pr.id AND prr.reviewer_id = $3
+			))
+			AND ($4::timestamptz IS NULL OR pr.created_at >= $4)
+			AND ($5::timestamptz IS NULL OR pr.created_at < $5)
+			AND ($6::timestamptz IS NULL OR pr.merged_at >= $6)
+			AND ($7::timestamptz IS NULL OR pr.merged_at < $7)
+			AND ($8::timestamptz IS NULL OR (pr.created_at, pr.id) < ($8, $9::uuid))
+		ORDER BY pr.created_at DESC, pr.id DESC
+		LIMIT $10;
+	`
 	insertReviewerQuery = `
-		INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
-		VALUES ($1, $2, $3);
+		INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, position, assigned_at, verdict, verdict_at)
+		VALUES ($1, $2, $3, $4, $5, $6);
 	`
 	deleteReviewersQuery = `
-		DELETE FROM pull_request_reviewers WHERE pull_request_id = $1;
+		DELETE FROM pull_request_reviewers
+		WHERE pull_request_id = $1 AND reviewer_id = ANY($2);
+	`
+	updateReviewerQuery = `
+		UPDATE pull_request_reviewers
+		SET position = $3, verdict = $4, verdict_at = $5
+		WHERE pull_request_id = $1 AND reviewer_id = $2;
+	`
+	selectReviewersForUpdateQuery = `
+		SELECT reviewer_id, position, verdict, verdict_at FROM pull_request_reviewers
+		WHERE pull_request_id = $1
+		FOR UPDATE;
+	`
+	insertAssignmentEventQuery = `
+		INSERT INTO assignment_events (pull_request_id, event_type, reviewer_id, previous_reviewer_id, actor, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at;
+	`
+	selectAssignmentEventsQuery = `
+		SELECT id, pull_request_id, event_type, reviewer_id, previous_reviewer_id, actor, reason, created_at
+		FROM assignment_events
+		WHERE pull_request_id = $1
+		ORDER BY id;
 	`
 	selectReviewersQuery = `
-		SELECT reviewer_id FROM pull_request_reviewers
-		WHERE pull_request_id = $1;
+		SELECT pull_request_id, reviewer_id, verdict, verdict_at FROM pull_request_reviewers
+		WHERE pull_request_id = ANY($1)
+		ORDER BY pull_request_id, position;
+	`
+	countOpenReviewsQuery = `
+		SELECT prr.reviewer_id, COUNT(*)
+		FROM pull_request_reviewers prr
+		JOIN pull_requests pr ON pr.id = prr.pull_request_id
+		JOIN pull_request_statuses s ON s.id = pr.status_id
+		WHERE s.name = 'OPEN' AND prr.reviewer_id = ANY($1)
+		GROUP BY prr.reviewer_id;
 	`
 )
 
 func (r *PullRequestRepository) Create(ctx context.Context, pr *models.PullRequest) error {
-	tx, err := r.db.Begin(ctx)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("create transaction: %w", err)
 	}
@@ -76,85 +135,63 @@ func (r *PullRequestRepository) Create(ctx context.Context, pr *models.PullReque
 	if err := tx.QueryRow(
 		ctx,
 		insertPullRequestQuery,
+		pr.ExternalID,
 		pr.Title,
 		pr.AuthorID,
 		pr.StatusID,
 		pr.MergedAt,
-	).Scan(&pr.ID, &pr.CreatedAt, &pr.UpdatedAt); err != nil {
-		return fmt.Errorf("insert pull request: %w", err)
+	).Scan(&pr.ID, &pr.CreatedAt, &pr.UpdatedAt, &pr.Version); err != nil {
+		return fmt.Errorf("insert pull request: %w", duplicateKey(err))
 	}
 
 	if len(pr.ReviewersIDs) > 0 {
-		if err := r.insertReviewersTx(ctx, tx, pr.ID, pr.ReviewersIDs); err != nil {
+		if err := r.insertReviewersTx(ctx, tx, pr, pr.ReviewersIDs); err != nil {
 			return fmt.Errorf("insert reviewers: %w", err)
 		}
 	}
 
+	if err := r.insertEventsTx(ctx, tx, pr); err != nil {
+		return err
+	}
+	if err := insertOutboxEventsTx(ctx, tx, pr.ID, pr.PendingEvents); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
+	pr.AssignmentEvents = nil
+	pr.PendingEvents = nil
 
 	return nil
 }
 
 func (r *PullRequestRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.PullRequest, error) {
-	var pr models.PullRequest
-
-	err := r.db.QueryRow(
-		ctx,
-		selectPullRequestByIDQuery,
-		id,
-	).Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.StatusID, &pr.MergedAt, &pr.CreatedAt, &pr.UpdatedAt)
-
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, ErrPullRequestNotFound
-	}
-	if err != nil {
+	pr, err := r.findOne(ctx, selectPullRequestByIDQuery, id)
+	if err != nil && !errors.Is(err, ErrPullRequestNotFound) {
 		return nil, fmt.Errorf("find pull request by id %s: %w", id, err)
 	}
+	return pr, err
+}
 
-	reviewers, err := r.getReviewers(ctx, pr.ID)
-	if err != nil {
-		return nil, fmt.Errorf("get reviewers for PR %s: %w", pr.ID, err)
+func (r *PullRequestRepository) FindByExternalID(ctx context.Context, externalID string) (*models.PullRequest, error) {
+	pr, err := r.findOne(ctx, selectPullRequestByExternalIDQuery, externalID)
+	if err != nil && !errors.Is(err, ErrPullRequestNotFound) {
+		return nil, fmt.Errorf("find pull request by external id %q: %w", externalID, err)
 	}
-	pr.ReviewersIDs = reviewers
-
-	return &pr, nil
+	return pr, err
 }
 
 func (r *PullRequestRepository) FindAll(ctx context.Context) ([]*models.PullRequest, error) {
-	rows, err := r.db.Query(ctx, selectAllPullRequestsQuery)
+	list, err := r.findMany(ctx, selectAllPullRequestsQuery)
 	if err != nil {
 		return nil, fmt.Errorf("find all pull requests: %w", err)
 	}
-	defer rows.Close()
-
-	var list []*models.PullRequest
-
-	for rows.Next() {
-		var pr models.PullRequest
-		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.StatusID, &pr.MergedAt, &pr.CreatedAt, &pr.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan pull request: %w", err)
-		}
-
-		reviewers, err := r.getReviewers(ctx, pr.ID)
-		if err != nil {
-			return nil, fmt.Errorf("get reviewers for PR %s: %w", pr.ID, err)
-		}
-		pr.ReviewersIDs = reviewers
-
-		list = append(list, &pr)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterating over pull request rows: %w", err)
-	}
-
 	return list, nil
 }
 
 func (r *PullRequestRepository) Update(ctx context.Context, pr *models.PullRequest) error {
-	tx, err := r.db.Begin(ctx)
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("start transaction for update: %w", err)
 	}
@@ -168,34 +205,45 @@ func (r *PullRequestRepository) Update(ctx context.Context, pr *models.PullReque
 		pr.StatusID,
 		pr.MergedAt,
 		pr.ID,
-	).Scan(&pr.UpdatedAt)
+		pr.Version,
+	).Scan(&pr.UpdatedAt, &pr.Version)
 
 	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, pullRequestExistsQuery, pr.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("check pull request %s: %w", pr.ID, err)
+		}
+		if exists {
+			return fmt.Errorf("update pull request %s: %w", pr.ID, repositories.ErrConcurrentModification)
+		}
 		return ErrPullRequestNotFound
 	}
 	if err != nil {
 		return fmt.Errorf("update pull request %s: %w", pr.ID, err)
 	}
 
-	if _, err := tx.Exec(ctx, deleteReviewersQuery, pr.ID); err != nil {
-		return fmt.Errorf("clear reviewers for PR %s: %w", pr.ID, err)
+	if err := r.syncReviewersTx(ctx, tx, pr); err != nil {
+		return err
 	}
 
-	if len(pr.ReviewersIDs) > 0 {
-		if err := r.insertReviewersTx(ctx, tx, pr.ID, pr.ReviewersIDs); err != nil {
-			return fmt.Errorf("re-insert reviewers for PR %s: %w", pr.ID, err)
-		}
+	if err := r.insertEventsTx(ctx, tx, pr); err != nil {
+		return err
+	}
+	if err := insertOutboxEventsTx(ctx, tx, pr.ID, pr.PendingEvents); err != nil {
+		return err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit transaction for update: %w", err)
 	}
+	pr.AssignmentEvents = nil
+	pr.PendingEvents = nil
 
 	return nil
 }
 
 func (r *PullRequestRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	cmd, err := r.db.Exec(ctx, deletePullRequestQuery, id)
+	cmd, err := conn(ctx, r.db).Exec(ctx, deletePullRequestQuery, id)
 	if err != nil {
 		return fmt.Errorf("delete pull request %s: %w", id, err)
 	}
@@ -208,66 +256,288 @@ func (r *PullRequestRepository) DeleteByID(ctx context.Context, id uuid.UUID) er
 }
 
 func (r *PullRequestRepository) FindByAuthor(ctx context.Context, authorID uuid.UUID) ([]*models.PullRequest, error) {
-	rows, err := r.db.Query(ctx, selectByAuthorQuery, authorID)
+	list, err := r.findMany(ctx, selectByAuthorQuery, authorID)
 	if err != nil {
 		return nil, fmt.Errorf("get pull requests by author %s: %w", authorID, err)
 	}
-	defer rows.Close()
+	return list, nil
+}
+
+func (r *PullRequestRepository) FindByReviewer(ctx context.Context, reviewerID uuid.UUID, filter repositories.PullRequestFilter) ([]*models.PullRequest, error) {
+	filter.ReviewerID = &reviewerID
+	return r.List(ctx, filter)
+}
+
+func (r *PullRequestRepository) List(ctx context.Context, filter repositories.PullRequestFilter) ([]*models.PullRequest, error) {
+	statuses := make([]string, 0, len(filter.Statuses))
+	for _, s := range filter.Statuses {
+		statuses = append(statuses, string(s))
+	}
+	authorIDs := filter.AuthorIDs
+	if authorIDs == nil {
+		authorIDs = []uuid.UUID{}
+	}
+
+	var afterCreatedAt *time.Time
+	var afterID *uuid.UUID
+	if filter.After != nil {
+		afterCreatedAt = &filter.After.CreatedAt
+		afterID = &filter.After.ID
+	}
+	var limit *int
+	if filter.Limit > 0 {
+		limit = &filter.Limit
+	}
+
+	list, err := r.findMany(
+		ctx,
+		listPullRequestsQuery,
+		statuses,
+		authorIDs,
+		filter.ReviewerID,
+		filter.CreatedFrom,
+		filter.CreatedTo,
+		filter.MergedFrom,
+		filter.MergedTo,
+		afterCreatedAt,
+		afterID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list pull requests: %w", err)
+	}
+	return list, nil
+}
+
+func (r *PullRequestRepository) CountOpenReviews(ctx context.Context, reviewerIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(reviewerIDs))
+	if len(reviewerIDs) == 0 {
+		return counts, nil
+	}
 
-	var list []*models.PullRequest
+	rows, err := conn(ctx, r.db).Query(ctx, countOpenReviewsQuery, reviewerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("count open reviews: %w", err)
+	}
+	defer rows.Close()
 
 	for rows.Next() {
-		var pr models.PullRequest
-		if err := rows.Scan(&pr.ID, &pr.Title, &pr.AuthorID, &pr.StatusID, &pr.MergedAt, &pr.CreatedAt, &pr.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("scan pull request: %w", err)
+		var id uuid.UUID
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("scan open review count: %w", err)
 		}
+		counts[id] = count
+	}
 
-		reviewers, err := r.getReviewers(ctx, pr.ID)
-		if err != nil {
-			return nil, fmt.Errorf("get reviewers for PR %s: %w", pr.ID, err)
-		}
-		pr.ReviewersIDs = reviewers
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating over open review counts: %w", err)
+	}
+
+	return counts, nil
+}
 
-		list = append(list, &pr)
+func (r *PullRequestRepository) FindAssignmentEvents(ctx context.Context, prID uuid.UUID) ([]*models.AssignmentEvent, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, selectAssignmentEventsQuery, prID)
+	if err != nil {
+		return nil, fmt.Errorf("get assignment events for PR %s: %w", prID, err)
+	}
+	defer rows.Close()
+
+	var events []*models.AssignmentEvent
+
+	for rows.Next() {
+		var e models.AssignmentEvent
+		if err := rows.Scan(&e.ID, &e.PullRequestID, &e.Type, &e.ReviewerID, &e.PreviousReviewerID, &e.Actor, &e.Reason, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan assignment event for PR %s: %w", prID, err)
+		}
+		events = append(events, &e)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterating over pull request rows for author %s: %w", authorID, err)
+		return nil, fmt.Errorf("iterating over assignment events for PR %s: %w", prID, err)
+	}
+
+	return events, nil
+}
+
+func (r *PullRequestRepository) findOne(ctx context.Context, query string, arg any) (*models.PullRequest, error) {
+	var pr models.PullRequest
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, arg).
+		Scan(&pr.ID, &pr.ExternalID, &pr.Title, &pr.AuthorID, &pr.StatusID, &pr.MergedAt, &pr.CreatedAt, &pr.UpdatedAt, &pr.Version)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPullRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.loadReviewers(ctx, []*models.PullRequest{&pr}); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// findMany scans the pull request rows first and then loads the reviewers of
+// all of them with a single query.
+func (r *PullRequestRepository) findMany(ctx context.Context, query string, args ...any) ([]*models.PullRequest, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	list, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.PullRequest, error) {
+		var pr models.PullRequest
+		err := row.Scan(&pr.ID, &pr.ExternalID, &pr.Title, &pr.AuthorID, &pr.StatusID, &pr.MergedAt, &pr.CreatedAt, &pr.UpdatedAt, &pr.Version)
+		return &pr, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan pull request: %w", err)
 	}
 
+	if err := r.loadReviewers(ctx, list); err != nil {
+		return nil, err
+	}
 	return list, nil
 }
 
-func (r *PullRequestRepository) getReviewers(ctx context.Context, prID uuid.UUID) ([]uuid.UUID, error) {
-	rows, err := r.db.Query(ctx, selectReviewersQuery, prID)
+func (r *PullRequestRepository) loadReviewers(ctx context.Context, prs []*models.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*models.PullRequest, len(prs))
+	ids := make([]uuid.UUID, 0, len(prs))
+	for _, pr := range prs {
+		pr.ReviewersIDs = nil
+		pr.Reviews = make(map[uuid.UUID]models.Review)
+		byID[pr.ID] = pr
+		ids = append(ids, pr.ID)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, selectReviewersQuery, ids)
 	if err != nil {
-		return nil, fmt.Errorf("get reviewers for PR %s: %w", prID, err)
+		return fmt.Errorf("get reviewers for %d PR(s): %w", len(ids), err)
 	}
 	defer rows.Close()
 
-	var reviewers []uuid.UUID
-
 	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("scan reviewer ID for PR %s: %w", prID, err)
+		var prID uuid.UUID
+		var review models.Review
+		if err := rows.Scan(&prID, &review.ReviewerID, &review.Verdict, &review.VerdictAt); err != nil {
+			return fmt.Errorf("scan reviewer: %w", err)
 		}
-		reviewers = append(reviewers, id)
+		pr := byID[prID]
+		pr.ReviewersIDs = append(pr.ReviewersIDs, review.ReviewerID)
+		pr.Reviews[review.ReviewerID] = review
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterating over reviewer rows for PR %s: %w", prID, err)
+		return fmt.Errorf("iterating over reviewer rows: %w", err)
 	}
 
-	return reviewers, nil
+	return nil
 }
 
-func (r *PullRequestRepository) insertReviewersTx(ctx context.Context, tx pgx.Tx, prID uuid.UUID, reviewers []uuid.UUID) error {
+// storedReviewer is a reviewer row as syncReviewersTx finds it.
+type storedReviewer struct {
+	models.Review
+	position int
+}
+
+// syncReviewersTx applies the difference between the stored reviewer rows and
+// pr.ReviewersIDs, so kept reviewers retain their assigned_at. Every row's
+// position is its index in pr.ReviewersIDs, so a replacement takes over the
+// slot of the reviewer it replaces.
+func (r *PullRequestRepository) syncReviewersTx(ctx context.Context, tx pgx.Tx, pr *models.PullRequest) error {
+	rows, err := tx.Query(ctx, selectReviewersForUpdateQuery, pr.ID)
+	if err != nil {
+		return fmt.Errorf("lock reviewers for PR %s: %w", pr.ID, err)
+	}
+	stored, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (storedReviewer, error) {
+		var old storedReviewer
+		err := row.Scan(&old.ReviewerID, &old.position, &old.Verdict, &old.VerdictAt)
+		return old, err
+	})
+	if err != nil {
+		return fmt.Errorf("scan reviewers for PR %s: %w", pr.ID, err)
+	}
+
+	var removed []uuid.UUID
+	for _, old := range stored {
+		position := slices.Index(pr.ReviewersIDs, old.ReviewerID)
+		if position < 0 {
+			removed = append(removed, old.ReviewerID)
+			continue
+		}
+		review := pr.Review(old.ReviewerID)
+		if position == old.position && review.Verdict == old.Verdict && equalTimes(review.VerdictAt, old.VerdictAt) {
+			continue
+		}
+		if _, err := tx.Exec(ctx, updateReviewerQuery, pr.ID, old.ReviewerID, position, review.Verdict, review.VerdictAt); err != nil {
+			return fmt.Errorf("update reviewer %s for PR %s: %w", old.ReviewerID, pr.ID, err)
+		}
+	}
+
+	if len(removed) > 0 {
+		if _, err := tx.Exec(ctx, deleteReviewersQuery, pr.ID, removed); err != nil {
+			return fmt.Errorf("remove reviewers for PR %s: %w", pr.ID, err)
+		}
+	}
+
+	var added []uuid.UUID
+	for _, id := range pr.ReviewersIDs {
+		if !slices.ContainsFunc(stored, func(old storedReviewer) bool { return old.ReviewerID == id }) {
+			added = append(added, id)
+		}
+	}
+	if len(added) > 0 {
+		if err := r.insertReviewersTx(ctx, tx, pr, added); err != nil {
+			return fmt.Errorf("add reviewers for PR %s: %w", pr.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepository) insertReviewersTx(ctx context.Context, tx pgx.Tx, pr *models.PullRequest, reviewers []uuid.UUID) error {
+	now := time.Now()
 	for _, reviewer := range reviewers {
-		_, err := tx.Exec(ctx, insertReviewerQuery, prID, reviewer, time.Now())
+		review := pr.Review(reviewer)
+		position := slices.Index(pr.ReviewersIDs, reviewer)
+		_, err := tx.Exec(ctx, insertReviewerQuery, pr.ID, reviewer, position, now, review.Verdict, review.VerdictAt)
 		if err != nil {
-			return fmt.Errorf("insert reviewer %s for PR %s: %w", reviewer, prID, err)
+			return fmt.Errorf("insert reviewer %s for PR %s: %w", reviewer, pr.ID, err)
 		}
 	}
 	return nil
 }
+
+func (r *PullRequestRepository) insertEventsTx(ctx context.Context, tx pgx.Tx, pr *models.PullRequest) error {
+	for i := range pr.AssignmentEvents {
+		e := &pr.AssignmentEvents[i]
+		e.PullRequestID = pr.ID
+		if err := tx.QueryRow(
+			ctx,
+			insertAssignmentEventQuery,
+			e.PullRequestID,
+			e.Type,
+			e.ReviewerID,
+			e.PreviousReviewerID,
+			e.Actor,
+			e.Reason,
+		).Scan(&e.ID, &e.CreatedAt); err != nil {
+			return fmt.Errorf("insert %s event for PR %s: %w", e.Type, pr.ID, err)
+		}
+	}
+	return nil
+}
+
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
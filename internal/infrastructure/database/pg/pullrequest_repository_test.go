@@ -0,0 +1,121 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/database/pg"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// seedReviews creates n open pull requests, each reviewed by the returned
+// reviewer and one other user.
+func seedReviews(tb testing.TB, pool *pgxpool.Pool, n int) uuid.UUID {
+	tb.Helper()
+
+	ctx := context.Background()
+	users := pg.NewUserRepository(pool)
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		u := &models.User{ExternalID: fmt.Sprintf("u%d", i), Username: fmt.Sprintf("user%d", i), IsActive: true}
+		if err := users.Create(ctx, u); err != nil {
+			tb.Fatal(err)
+		}
+		ids = append(ids, u.ID)
+	}
+	author, reviewer, other := ids[0], ids[1], ids[2]
+
+	statuses, err := pg.NewStatusRepository(pool).FindAll(ctx)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var openID uuid.UUID
+	for _, s := range statuses {
+		if s.StatusName() == models.StatusOpen {
+			openID = s.ID
+		}
+	}
+
+	prs := pg.NewPullRequestRepository(pool)
+	for i := 0; i < n; i++ {
+		pr := &models.PullRequest{
+			ExternalID:   fmt.Sprintf("pr-%d", i),
+			Title:        "benchmark",
+			AuthorID:     author,
+			StatusID:     openID,
+			ReviewersIDs: []uuid.UUID{reviewer, other},
+		}
+		if err := prs.Create(ctx, pr); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return reviewer
+}
+
+// TestFindByReviewerQueryCount checks that reviewers are loaded for all pull
+// requests at once instead of one query per pull request.
+func TestFindByReviewerQueryCount(t *testing.T) {
+	counter := &queryCounter{}
+	pool := testPool(t, counter)
+	reviewer := seedReviews(t, pool, 25)
+	repo := pg.NewPullRequestRepository(pool)
+
+	counter.n.Store(0)
+	list, err := repo.FindByReviewer(context.Background(), reviewer, repositories.PullRequestFilter{})
+	if err != nil {
+		t.Fatalf("FindByReviewer: %v", err)
+	}
+	if len(list) != 25 {
+		t.Fatalf("got %d pull requests, want 25", len(list))
+	}
+	for _, pr := range list {
+		if len(pr.ReviewersIDs) != 2 {
+			t.Errorf("PR %s has reviewers %v, want 2", pr.ExternalID, pr.ReviewersIDs)
+		}
+	}
+	if n := counter.n.Load(); n != 2 {
+		t.Errorf("FindByReviewer sent %d queries, want 2", n)
+	}
+}
+
+func BenchmarkFindByReviewer(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("prs=%d", n), func(b *testing.B) {
+			counter := &queryCounter{}
+			pool := testPool(b, counter)
+			reviewer := seedReviews(b, pool, n)
+			repo := pg.NewPullRequestRepository(pool)
+			ctx := context.Background()
+
+			counter.n.Store(0)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.FindByReviewer(ctx, reviewer, repositories.PullRequestFilter{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(counter.n.Load())/float64(b.N), "queries/op")
+		})
+	}
+}
+
+func BenchmarkListPage(b *testing.B) {
+	counter := &queryCounter{}
+	pool := testPool(b, counter)
+	seedReviews(b, pool, 1000)
+	repo := pg.NewPullRequestRepository(pool)
+	ctx := context.Background()
+
+	counter.n.Store(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.List(ctx, repositories.PullRequestFilter{Statuses: []models.StatusName{models.StatusOpen}, Limit: 50}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(counter.n.Load())/float64(b.N), "queries/op")
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"errors"
 	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrStatusNotFound = errors.New("status not found")
+var ErrStatusNotFound = repositories.ErrStatusNotFound
 
 type StatusRepository struct {
 	db *pgxpool.Pool
@@ -27,7 +28,7 @@ const (
 
 func (r *StatusRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Status, error) {
 	var s models.Status
-	if err := r.db.QueryRow(ctx, getStatusByIDQuery, id).Scan(&s.ID, &s.Name); err != nil {
+	if err := conn(ctx, r.db).QueryRow(ctx, getStatusByIDQuery, id).Scan(&s.ID, &s.Name); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrStatusNotFound
 		}
@@ -37,7 +38,7 @@ func (r *StatusRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.
 }
 
 func (r *StatusRepository) FindAll(ctx context.Context) ([]*models.Status, error) {
-	rows, err := r.db.Query(ctx, listStatusesQuery)
+	rows, err := conn(ctx, r.db).Query(ctx, listStatusesQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -3,14 +3,16 @@ package pg
 import (
 	"context"
 	"errors"
+	"fmt"
 	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrTeamNotFound = errors.New("team not found")
+var ErrTeamNotFound = repositories.ErrTeamNotFound
 
 type TeamRepository struct {
 	db *pgxpool.Pool
@@ -21,27 +23,28 @@ func NewTeamRepository(db *pgxpool.Pool) *TeamRepository {
 }
 
 const (
-	insertTeamQuery         = `INSERT INTO teams (name) VALUES ($1) RETURNING id`
-	updateTeamQuery         = `UPDATE teams SET name=$1 WHERE id=$2`
+	insertTeamQuery         = `INSERT INTO teams (name) VALUES ($1) RETURNING id, created_at, updated_at, version`
+	updateTeamQuery         = `UPDATE teams SET name=$1, updated_at=now(), version=version+1 WHERE id=$2 AND version=$3 RETURNING updated_at, version`
+	teamExistsQuery         = `SELECT EXISTS (SELECT 1 FROM teams WHERE id=$1)`
 	deleteTeamUsersQuery    = `DELETE FROM team_user WHERE team_id=$1`
 	deleteTeamQuery         = `DELETE FROM teams WHERE id=$1`
 	insertTeamUserQuery     = `INSERT INTO team_user (team_id, user_id) VALUES ($1, $2)`
-	selectTeamByIDQuery     = `SELECT id, name, created_at, updated_at FROM teams WHERE id=$1`
-	selectTeamByNameQuery   = `SELECT id, name, created_at, updated_at FROM teams WHERE name=$1`
+	selectTeamByIDQuery     = `SELECT id, name, created_at, updated_at, version FROM teams WHERE id=$1`
+	selectTeamByNameQuery   = `SELECT id, name, created_at, updated_at, version FROM teams WHERE name=$1`
 	selectTeamUsersQuery    = `SELECT user_id FROM team_user WHERE team_id=$1`
-	selectAllTeamsQuery     = `SELECT id, name, created_at, updated_at FROM teams ORDER BY created_at DESC`
-	selectTeamByUserIDQuery = `SELECT t.id, t.name, t.created_at, t.updated_at FROM teams t JOIN team_user tu ON t.id = tu.team_id WHERE tu.user_id = $1`
+	selectAllTeamsQuery     = `SELECT id, name, created_at, updated_at, version FROM teams ORDER BY created_at DESC`
+	selectTeamByUserIDQuery = `SELECT t.id, t.name, t.created_at, t.updated_at, t.version FROM teams t JOIN team_user tu ON t.id = tu.team_id WHERE tu.user_id = $1`
 )
 
 func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	if err := tx.QueryRow(ctx, insertTeamQuery, team.Name).Scan(&team.ID); err != nil {
-		return err
+	if err := tx.QueryRow(ctx, insertTeamQuery, team.Name).Scan(&team.ID, &team.CreatedAt, &team.UpdatedAt, &team.Version); err != nil {
+		return duplicateKey(err)
 	}
 
 	for _, uid := range team.UserIDs {
@@ -56,14 +59,14 @@ func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 func (r *TeamRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Team, error) {
 	team := &models.Team{UserIDs: []uuid.UUID{}}
 
-	err := r.db.QueryRow(ctx, selectTeamByIDQuery, id).Scan(
-		&team.ID, &team.Name, &team.CreatedAt, &team.UpdatedAt,
+	err := conn(ctx, r.db).QueryRow(ctx, selectTeamByIDQuery, id).Scan(
+		&team.ID, &team.Name, &team.CreatedAt, &team.UpdatedAt, &team.Version,
 	)
 	if err != nil {
 		return nil, ErrTeamNotFound
 	}
 
-	rows, err := r.db.Query(ctx, selectTeamUsersQuery, id)
+	rows, err := conn(ctx, r.db).Query(ctx, selectTeamUsersQuery, id)
 	if err != nil {
 		return nil, err
 	}
@@ -81,7 +84,7 @@ func (r *TeamRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Te
 }
 
 func (r *TeamRepository) FindAll(ctx context.Context) ([]*models.Team, error) {
-	rows, err := r.db.Query(ctx, selectAllTeamsQuery)
+	rows, err := conn(ctx, r.db).Query(ctx, selectAllTeamsQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -92,11 +95,20 @@ func (r *TeamRepository) FindAll(ctx context.Context) ([]*models.Team, error) {
 		var t models.Team
 		t.UserIDs = []uuid.UUID{}
 
-		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		if err := rows.Scan(&t.ID, &t.Name, &t.CreatedAt, &t.UpdatedAt, &t.Version); err != nil {
 			return nil, err
 		}
+		teams = append(teams, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		memberRows, err := r.db.Query(ctx, selectTeamUsersQuery, t.ID)
+	// Members are loaded once the team rows are drained: inside a unit of
+	// work every query shares one connection.
+	for _, t := range teams {
+		memberRows, err := conn(ctx, r.db).Query(ctx, selectTeamUsersQuery, t.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -109,26 +121,35 @@ func (r *TeamRepository) FindAll(ctx context.Context) ([]*models.Team, error) {
 			t.UserIDs = append(t.UserIDs, uid)
 		}
 		memberRows.Close()
-		teams = append(teams, &t)
+		if err := memberRows.Err(); err != nil {
+			return nil, err
+		}
 	}
 
-	return teams, rows.Err()
+	return teams, nil
 }
 
 func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 
-	cmd, err := tx.Exec(ctx, updateTeamQuery, team.Name, team.ID)
-	if err != nil {
-		return err
-	}
-	if cmd.RowsAffected() == 0 {
+	err = tx.QueryRow(ctx, updateTeamQuery, team.Name, team.ID, team.Version).Scan(&team.UpdatedAt, &team.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRow(ctx, teamExistsQuery, team.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("update team %s: %w", team.ID, repositories.ErrConcurrentModification)
+		}
 		return ErrTeamNotFound
 	}
+	if err != nil {
+		return duplicateKey(err)
+	}
 
 	if _, err = tx.Exec(ctx, deleteTeamUsersQuery, team.ID); err != nil {
 		return err
@@ -144,7 +165,7 @@ func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
 }
 
 func (r *TeamRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
+	tx, err := conn(ctx, r.db).Begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -165,14 +186,14 @@ func (r *TeamRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
 func (r *TeamRepository) FindByName(ctx context.Context, name string) (*models.Team, error) {
 	team := &models.Team{UserIDs: []uuid.UUID{}}
 
-	err := r.db.QueryRow(ctx, selectTeamByNameQuery, name).Scan(
-		&team.ID, &team.Name, &team.CreatedAt, &team.UpdatedAt,
+	err := conn(ctx, r.db).QueryRow(ctx, selectTeamByNameQuery, name).Scan(
+		&team.ID, &team.Name, &team.CreatedAt, &team.UpdatedAt, &team.Version,
 	)
 	if err != nil {
 		return nil, ErrTeamNotFound
 	}
 
-	rows, err := r.db.Query(ctx, selectTeamUsersQuery, team.ID)
+	rows, err := conn(ctx, r.db).Query(ctx, selectTeamUsersQuery, team.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -192,14 +213,14 @@ func (r *TeamRepository) FindByName(ctx context.Context, name string) (*models.T
 func (r *TeamRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*models.Team, error) {
 	team := &models.Team{UserIDs: []uuid.UUID{}}
 
-	err := r.db.QueryRow(ctx, selectTeamByUserIDQuery, userID).Scan(
-		&team.ID, &team.Name, &team.CreatedAt, &team.UpdatedAt,
+	err := conn(ctx, r.db).QueryRow(ctx, selectTeamByUserIDQuery, userID).Scan(
+		&team.ID, &team.Name, &team.CreatedAt, &team.UpdatedAt, &team.Version,
 	)
 	if err != nil {
 		return nil, ErrTeamNotFound
 	}
 
-	rows, err := r.db.Query(ctx, selectTeamUsersQuery, team.ID)
+	rows, err := conn(ctx, r.db).Query(ctx, selectTeamUsersQuery, team.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,73 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrTeamSettingsNotFound = repositories.ErrTeamSettingsNotFound
+
+type TeamSettingsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTeamSettingsRepository(db *pgxpool.Pool) *TeamSettingsRepository {
+	return &TeamSettingsRepository{db: db}
+}
+
+const (
+	selectTeamSettingsQuery = `
+		SELECT team_id, reviewer_count, min_reviewers, required_approvals, selection_strategy, created_at, updated_at
+		FROM team_settings
+		WHERE team_id = $1;
+	`
+	upsertTeamSettingsQuery = `
+		INSERT INTO team_settings (team_id, reviewer_count, min_reviewers, required_approvals, selection_strategy)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (team_id) DO UPDATE
+		SET reviewer_count = EXCLUDED.reviewer_count,
+		    min_reviewers = EXCLUDED.min_reviewers,
+		    required_approvals = EXCLUDED.required_approvals,
+		    selection_strategy = EXCLUDED.selection_strategy
+		RETURNING created_at, updated_at;
+	`
+)
+
+func (r *TeamSettingsRepository) FindByTeamID(ctx context.Context, teamID uuid.UUID) (*models.TeamSettings, error) {
+	var s models.TeamSettings
+
+	err := conn(ctx, r.db).QueryRow(ctx, selectTeamSettingsQuery, teamID).
+		Scan(&s.TeamID, &s.ReviewerCount, &s.MinReviewers, &s.RequiredApprovals, &s.SelectionStrategy, &s.CreatedAt, &s.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTeamSettingsNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find settings for team %s: %w", teamID, err)
+	}
+
+	return &s, nil
+}
+
+func (r *TeamSettingsRepository) Upsert(ctx context.Context, settings *models.TeamSettings) error {
+	if err := conn(ctx, r.db).QueryRow(
+		ctx,
+		upsertTeamSettingsQuery,
+		settings.TeamID,
+		settings.ReviewerCount,
+		settings.MinReviewers,
+		settings.RequiredApprovals,
+		settings.SelectionStrategy,
+	).Scan(&settings.CreatedAt, &settings.UpdatedAt); err != nil {
+		return fmt.Errorf("upsert settings for team %s: %w", settings.TeamID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UnavailabilityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUnavailabilityRepository(db *pgxpool.Pool) *UnavailabilityRepository {
+	return &UnavailabilityRepository{db: db}
+}
+
+const (
+	insertUnavailabilityQuery = `
+		INSERT INTO user_unavailability (user_id, starts_at, ends_at, reason, source_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at;
+	`
+	selectUnavailabilityByUserQuery = `
+		SELECT id, user_id, starts_at, ends_at, reason, source_id, created_at
+		FROM user_unavailability
+		WHERE user_id = $1
+		ORDER BY starts_at, id;
+	`
+	deleteUnavailabilityQuery = `
+		DELETE FROM user_unavailability WHERE id = $1;
+	`
+	deleteUnavailabilityBySourceQuery = `
+		DELETE FROM user_unavailability WHERE source_id = $1;
+	`
+	selectUnavailableUserIDsQuery = `
+		SELECT DISTINCT user_id
+		FROM user_unavailability
+		WHERE user_id = ANY($1) AND starts_at <= $2 AND ends_at > $2;
+	`
+)
+
+func (r *UnavailabilityRepository) Create(ctx context.Context, period *models.UserUnavailability) error {
+	if err := conn(ctx, r.db).QueryRow(
+		ctx,
+		insertUnavailabilityQuery,
+		period.UserID,
+		period.StartsAt,
+		period.EndsAt,
+		period.Reason,
+		period.SourceID,
+	).Scan(&period.ID, &period.CreatedAt); err != nil {
+		return fmt.Errorf("insert unavailability for user %s: %w", period.UserID, err)
+	}
+	return nil
+}
+
+func (r *UnavailabilityRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserUnavailability, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, selectUnavailabilityByUserQuery, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find unavailability for user %s: %w", userID, err)
+	}
+
+	periods, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.UserUnavailability, error) {
+		var p models.UserUnavailability
+		err := row.Scan(&p.ID, &p.UserID, &p.StartsAt, &p.EndsAt, &p.Reason, &p.SourceID, &p.CreatedAt)
+		return &p, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan unavailability for user %s: %w", userID, err)
+	}
+
+	return periods, nil
+}
+
+func (r *UnavailabilityRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.db).Exec(ctx, deleteUnavailabilityQuery, id)
+	if err != nil {
+		return fmt.Errorf("delete unavailability %s: %w", id, err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return repositories.ErrUnavailabilityNotFound
+	}
+
+	return nil
+}
+
+func (r *UnavailabilityRepository) DeleteBySourceID(ctx context.Context, sourceID uuid.UUID) error {
+	if _, err := conn(ctx, r.db).Exec(ctx, deleteUnavailabilityBySourceQuery, sourceID); err != nil {
+		return fmt.Errorf("delete unavailability of calendar source %s: %w", sourceID, err)
+	}
+	return nil
+}
+
+func (r *UnavailabilityRepository) FindUnavailableUserIDs(ctx context.Context, userIDs []uuid.UUID, at time.Time) (map[uuid.UUID]bool, error) {
+	unavailable := make(map[uuid.UUID]bool)
+	if len(userIDs) == 0 {
+		return unavailable, nil
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, selectUnavailableUserIDsQuery, userIDs, at)
+	if err != nil {
+		return nil, fmt.Errorf("find unavailable users: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("scan unavailable users: %w", err)
+	}
+
+	for _, id := range ids {
+		unavailable[id] = true
+	}
+	return unavailable, nil
+}
@@ -0,0 +1,91 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrUserIdentityNotFound = repositories.ErrUserIdentityNotFound
+
+type UserIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserIdentityRepository(db *pgxpool.Pool) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+const (
+	selectUserIdentityQuery = `
+		SELECT provider, login, user_id, created_at
+		FROM user_identities
+		WHERE provider = $1 AND login = $2;
+	`
+	selectUserIdentityByUserQuery = `
+		SELECT provider, login, user_id, created_at
+		FROM user_identities
+		WHERE provider = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1;
+	`
+	upsertUserIdentityQuery = `
+		INSERT INTO user_identities (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE
+		SET user_id = EXCLUDED.user_id
+		RETURNING created_at;
+	`
+)
+
+func (r *UserIdentityRepository) FindByLogin(ctx context.Context, provider string, login string) (*models.UserIdentity, error) {
+	id, err := r.findOne(ctx, selectUserIdentityQuery, provider, login)
+	if err != nil && !errors.Is(err, ErrUserIdentityNotFound) {
+		return nil, fmt.Errorf("find %s identity %q: %w", provider, login, err)
+	}
+	return id, err
+}
+
+func (r *UserIdentityRepository) FindByUserID(ctx context.Context, provider string, userID uuid.UUID) (*models.UserIdentity, error) {
+	id, err := r.findOne(ctx, selectUserIdentityByUserQuery, provider, userID)
+	if err != nil && !errors.Is(err, ErrUserIdentityNotFound) {
+		return nil, fmt.Errorf("find %s identity of user %s: %w", provider, userID, err)
+	}
+	return id, err
+}
+
+func (r *UserIdentityRepository) findOne(ctx context.Context, query string, args ...any) (*models.UserIdentity, error) {
+	var id models.UserIdentity
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, args...).
+		Scan(&id.Provider, &id.Login, &id.UserID, &id.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserIdentityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+func (r *UserIdentityRepository) Upsert(ctx context.Context, identity *models.UserIdentity) error {
+	if err := conn(ctx, r.db).QueryRow(
+		ctx,
+		upsertUserIdentityQuery,
+		identity.Provider,
+		identity.Login,
+		identity.UserID,
+	).Scan(&identity.CreatedAt); err != nil {
+		return fmt.Errorf("upsert %s identity %q: %w", identity.Provider, identity.Login, err)
+	}
+
+	return nil
+}
@@ -5,13 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var ErrUserNotFound = errors.New("user not found")
+var ErrUserNotFound = repositories.ErrUserNotFound
 
 type UserRepository struct {
 	db *pgxpool.Pool
@@ -22,17 +23,18 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 }
 
 const (
-	insertUserQuery     = `INSERT INTO users (username, is_active) VALUES ($1, $2) RETURNING id, created_at, updated_at;`
-	selectUserByIDQuery = `SELECT id, username, is_active, created_at, updated_at FROM users WHERE id = $1;`
-	selectAllUsersQuery = `SELECT id, username, is_active, created_at, updated_at FROM users ORDER BY created_at DESC;`
-	updateUserQuery     = `UPDATE users SET username = $1, is_active = $2, updated_at = now() WHERE id = $3 RETURNING updated_at;`
-	deleteUserQuery     = `DELETE FROM users WHERE id = $1;`
+	insertUserQuery             = `INSERT INTO users (external_id, username, is_active) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at;`
+	selectUserByIDQuery         = `SELECT id, external_id, username, is_active, created_at, updated_at FROM users WHERE id = $1;`
+	selectUserByExternalIDQuery = `SELECT id, external_id, username, is_active, created_at, updated_at FROM users WHERE external_id = $1;`
+	selectAllUsersQuery         = `SELECT id, external_id, username, is_active, created_at, updated_at FROM users ORDER BY created_at DESC;`
+	updateUserQuery             = `UPDATE users SET username = $1, is_active = $2, updated_at = now() WHERE id = $3 RETURNING updated_at;`
+	deleteUserQuery             = `DELETE FROM users WHERE id = $1;`
 )
 
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
-	if err := r.db.QueryRow(ctx, insertUserQuery, user.Username, user.IsActive).
+	if err := conn(ctx, r.db).QueryRow(ctx, insertUserQuery, user.ExternalID, user.Username, user.IsActive).
 		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt); err != nil {
-		return fmt.Errorf("create user: %w", err)
+		return fmt.Errorf("create user: %w", duplicateKey(err))
 	}
 
 	return nil
@@ -41,8 +43,8 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	u := models.User{}
 
-	err := r.db.QueryRow(ctx, selectUserByIDQuery, id).
-		Scan(&u.ID, &u.Username, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+	err := conn(ctx, r.db).QueryRow(ctx, selectUserByIDQuery, id).
+		Scan(&u.ID, &u.ExternalID, &u.Username, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, ErrUserNotFound
@@ -54,8 +56,24 @@ func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Us
 	return &u, nil
 }
 
+func (r *UserRepository) FindByExternalID(ctx context.Context, externalID string) (*models.User, error) {
+	u := models.User{}
+
+	err := conn(ctx, r.db).QueryRow(ctx, selectUserByExternalIDQuery, externalID).
+		Scan(&u.ID, &u.ExternalID, &u.Username, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find user by external id %q: %w", externalID, err)
+	}
+
+	return &u, nil
+}
+
 func (r *UserRepository) FindAll(ctx context.Context) ([]*models.User, error) {
-	rows, err := r.db.Query(ctx, selectAllUsersQuery)
+	rows, err := conn(ctx, r.db).Query(ctx, selectAllUsersQuery)
 	if err != nil {
 		return nil, fmt.Errorf("find all users: %w", err)
 	}
@@ -65,7 +83,7 @@ func (r *UserRepository) FindAll(ctx context.Context) ([]*models.User, error) {
 
 	for rows.Next() {
 		var u models.User
-		if err = rows.Scan(&u.ID, &u.Username, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		if err = rows.Scan(&u.ID, &u.ExternalID, &u.Username, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan user: %w", err)
 		}
 		list = append(list, &u)
@@ -79,7 +97,7 @@ func (r *UserRepository) FindAll(ctx context.Context) ([]*models.User, error) {
 }
 
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
-	err := r.db.QueryRow(
+	err := conn(ctx, r.db).QueryRow(
 		ctx,
 		updateUserQuery,
 		user.Username,
@@ -91,14 +109,14 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 		return ErrUserNotFound
 	}
 	if err != nil {
-		return fmt.Errorf("update user %s: %w", user.ID, err)
+		return fmt.Errorf("update user %s: %w", user.ID, duplicateKey(err))
 	}
 
 	return nil
 }
 
 func (r *UserRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	cmd, err := r.db.Exec(ctx, deleteUserQuery, id)
+	cmd, err := conn(ctx, r.db).Exec(ctx, deleteUserQuery, id)
 	if err != nil {
 		return fmt.Errorf("delete user %s: %w", id, err)
 	}
@@ -0,0 +1,133 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrWebhookNotFound = repositories.ErrWebhookNotFound
+
+type WebhookSubscriptionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookSubscriptionRepository(db *pgxpool.Pool) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{db: db}
+}
+
+const (
+	insertWebhookSubscriptionQuery = `
+		INSERT INTO webhook_subscriptions (url, secret, event_types)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at;
+	`
+	selectAllWebhookSubscriptionsQuery = `
+		SELECT id, url, secret, event_types, created_at, updated_at
+		FROM webhook_subscriptions
+		ORDER BY created_at DESC;
+	`
+	selectWebhookSubscriptionsByEventQuery = `
+		SELECT id, url, secret, event_types, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE cardinality(event_types) = 0 OR $1 = ANY(event_types)
+		ORDER BY created_at;
+	`
+	deleteWebhookSubscriptionQuery = `
+		DELETE FROM webhook_subscriptions WHERE id = $1;
+	`
+	insertWebhookDeadLetterQuery = `
+		INSERT INTO webhook_dead_letters (subscription_id, event_id, event_type, url, payload, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at;
+	`
+)
+
+func (r *WebhookSubscriptionRepository) Create(ctx context.Context, sub *models.WebhookSubscription) error {
+	if err := conn(ctx, r.db).QueryRow(
+		ctx,
+		insertWebhookSubscriptionQuery,
+		sub.URL,
+		sub.Secret,
+		eventTypesOrEmpty(sub.EventTypes),
+	).Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		return fmt.Errorf("insert webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) FindAll(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	return r.query(ctx, selectAllWebhookSubscriptionsQuery)
+}
+
+func (r *WebhookSubscriptionRepository) FindByEventType(ctx context.Context, eventType string) ([]*models.WebhookSubscription, error) {
+	return r.query(ctx, selectWebhookSubscriptionsByEventQuery, eventType)
+}
+
+func (r *WebhookSubscriptionRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
+	cmd, err := conn(ctx, r.db).Exec(ctx, deleteWebhookSubscriptionQuery, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook subscription %s: %w", id, err)
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) query(ctx context.Context, query string, args ...any) ([]*models.WebhookSubscription, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("find webhook subscriptions: %w", err)
+	}
+
+	subs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*models.WebhookSubscription, error) {
+		var s models.WebhookSubscription
+		err := row.Scan(&s.ID, &s.URL, &s.Secret, &s.EventTypes, &s.CreatedAt, &s.UpdatedAt)
+		return &s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan webhook subscriptions: %w", err)
+	}
+
+	return subs, nil
+}
+
+type WebhookDeadLetterRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookDeadLetterRepository(db *pgxpool.Pool) *WebhookDeadLetterRepository {
+	return &WebhookDeadLetterRepository{db: db}
+}
+
+func (r *WebhookDeadLetterRepository) Create(ctx context.Context, dl *models.WebhookDeadLetter) error {
+	if err := conn(ctx, r.db).QueryRow(
+		ctx,
+		insertWebhookDeadLetterQuery,
+		dl.SubscriptionID,
+		dl.EventID,
+		dl.EventType,
+		dl.URL,
+		dl.Payload,
+		dl.Attempts,
+		dl.LastError,
+	).Scan(&dl.ID, &dl.CreatedAt); err != nil {
+		return fmt.Errorf("insert webhook dead letter for event %s: %w", dl.EventID, err)
+	}
+	return nil
+}
+
+func eventTypesOrEmpty(types []string) []string {
+	if types == nil {
+		return []string{}
+	}
+	return types
+}
@@ -0,0 +1,107 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"pullrequest-manager/internal/domain/models"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// outboxLease is long enough that no lease runs out during the test.
+const outboxLease = time.Minute
+
+// createPullRequestWithEvents writes a pull request together with one outbox
+// event per type, each a millisecond after the one before.
+func createPullRequestWithEvents(t *testing.T, r Repositories, externalID string, author uuid.UUID, start time.Time, eventTypes ...string) {
+	t.Helper()
+
+	pr := &models.PullRequest{
+		ExternalID: externalID,
+		Title:      "outbox",
+		AuthorID:   author,
+		StatusID:   statusID(t, r, models.StatusOpen),
+	}
+	for i, eventType := range eventTypes {
+		pr.AddPendingEvent(models.OutboxEvent{
+			ID:         uuid.New(),
+			EventType:  eventType,
+			Payload:    []byte(`{"pull_request_id":"` + externalID + `"}`),
+			OccurredAt: start.Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+	if err := r.PullRequests.Create(context.Background(), pr); err != nil {
+		t.Fatalf("create pull request %s: %v", externalID, err)
+	}
+}
+
+// outboxPass runs one Process pass, failing the events whose type is in
+// failing, and returns the types handed out in order.
+func outboxPass(t *testing.T, r Repositories, retryDelay time.Duration, failing ...string) []string {
+	t.Helper()
+
+	var handled []string
+	_, err := r.Outbox.Process(context.Background(), 10, 5, outboxLease,
+		func(int) time.Duration { return retryDelay },
+		func(ctx context.Context, e *models.OutboxEvent) error {
+			handled = append(handled, e.EventType)
+			if slices.Contains(failing, e.EventType) {
+				return errors.New("receiver is down")
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	return handled
+}
+
+func testOutbox(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	author := createUser(t, r, "u1", "Alice")
+	start := time.Now().UTC().Add(-time.Minute).Truncate(time.Millisecond)
+
+	createPullRequestWithEvents(t, r, "pr-a", author.ID, start, "a1", "a2")
+	createPullRequestWithEvents(t, r, "pr-b", author.ID, start.Add(10*time.Millisecond), "b1", "b2")
+
+	// A failure holds back the rest of its pull request, not the others.
+	if got := outboxPass(t, r, time.Hour, "a1"); !slices.Equal(got, []string{"a1", "b1", "b2"}) {
+		t.Fatalf("first pass handled %v, want a1, b1, b2", got)
+	}
+	// a1 waits for its retry, and a2 waits behind it.
+	if got := outboxPass(t, r, time.Hour); len(got) != 0 {
+		t.Fatalf("pass during backoff handled %v, want nothing", got)
+	}
+
+	createPullRequestWithEvents(t, r, "pr-c", author.ID, start.Add(20*time.Millisecond), "c1")
+
+	// Events leased by a running pass are skipped by another one.
+	var nested []string
+	_, err := r.Outbox.Process(ctx, 10, 5, outboxLease,
+		func(int) time.Duration { return time.Hour },
+		func(ctx context.Context, e *models.OutboxEvent) error {
+			nested = outboxPass(t, r, time.Hour)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(nested) != 0 {
+		t.Errorf("concurrent pass handled %v, want nothing", nested)
+	}
+	if got := outboxPass(t, r, time.Hour); len(got) != 0 {
+		t.Errorf("pass after publishing c1 handled %v, want nothing", got)
+	}
+
+	// Events past maxAttempts are not handed out again.
+	createPullRequestWithEvents(t, r, "pr-d", author.ID, start.Add(30*time.Millisecond), "d1")
+	for i := 0; i < 5; i++ {
+		outboxPass(t, r, 0, "d1")
+	}
+	if got := outboxPass(t, r, 0); len(got) != 0 {
+		t.Errorf("pass after the last attempt handled %v, want nothing", got)
+	}
+}
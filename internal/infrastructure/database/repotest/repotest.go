@@ -0,0 +1,406 @@
+// Package repotest holds the contract every storage backend has to meet. The
+// memory and pg packages run it from their tests, so both keep the same
+// not-found, duplicate-key and optimistic-locking semantics.
+package repotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repositories is one backend's implementation of the repositories under
+// contract, all sharing the same storage.
+type Repositories struct {
+	Users        repositories.User
+	Teams        repositories.Team
+	Statuses     repositories.Status
+	PullRequests repositories.PullRequest
+	// Outbox receives the events written with PullRequests.
+	Outbox repositories.Outbox
+}
+
+// Run runs the contract against fresh, empty repositories from newRepos,
+// called once per subtest.
+func Run(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	t.Run("User", func(t *testing.T) { testUser(t, newRepos(t)) })
+	t.Run("Team", func(t *testing.T) { testTeam(t, newRepos(t)) })
+	t.Run("Status", func(t *testing.T) { testStatus(t, newRepos(t)) })
+	t.Run("PullRequest", func(t *testing.T) { testPullRequest(t, newRepos(t)) })
+	t.Run("PullRequestQueries", func(t *testing.T) { testPullRequestQueries(t, newRepos(t)) })
+	t.Run("Outbox", func(t *testing.T) { testOutbox(t, newRepos(t)) })
+}
+
+func testUser(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	alice := createUser(t, r, "u1", "Alice")
+	if alice.ID == uuid.Nil || alice.CreatedAt.IsZero() {
+		t.Fatalf("Create did not fill in ID and CreatedAt: %+v", alice)
+	}
+
+	got, err := r.Users.FindByExternalID(ctx, "u1")
+	if err != nil || got.ID != alice.ID || got.Username != "Alice" || !got.IsActive {
+		t.Errorf("FindByExternalID = %+v, %v", got, err)
+	}
+
+	alice.Username = "Alice B."
+	alice.IsActive = false
+	if err := r.Users.Update(ctx, alice); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err = r.Users.FindByID(ctx, alice.ID)
+	if err != nil || got.Username != "Alice B." || got.IsActive {
+		t.Errorf("FindByID after Update = %+v, %v", got, err)
+	}
+
+	bob := createUser(t, r, "u2", "Bob")
+	for name, err := range map[string]error{
+		"Create with taken username":    r.Users.Create(ctx, &models.User{ExternalID: "u3", Username: "Bob"}),
+		"Create with taken external id": r.Users.Create(ctx, &models.User{ExternalID: "u2", Username: "Carol"}),
+		"Update to taken username":      r.Users.Update(ctx, &models.User{ID: alice.ID, Username: bob.Username}),
+	} {
+		if !errors.Is(err, repositories.ErrDuplicateKey) {
+			t.Errorf("%s = %v, want ErrDuplicateKey", name, err)
+		}
+	}
+
+	if list, err := r.Users.FindAll(ctx); err != nil || len(list) != 2 {
+		t.Errorf("FindAll = %d users, %v; want 2", len(list), err)
+	}
+
+	if err := r.Users.DeleteByID(ctx, bob.ID); err != nil {
+		t.Fatalf("DeleteByID: %v", err)
+	}
+	missing := bob.ID
+	checkNotFound(t, repositories.ErrUserNotFound, map[string]error{
+		"FindByID":         second(r.Users.FindByID(ctx, missing)),
+		"FindByExternalID": second(r.Users.FindByExternalID(ctx, "missing")),
+		"Update":           r.Users.Update(ctx, &models.User{ID: missing, Username: "ghost"}),
+		"DeleteByID":       r.Users.DeleteByID(ctx, missing),
+	})
+}
+
+func testTeam(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	alice := createUser(t, r, "u1", "Alice")
+	bob := createUser(t, r, "u2", "Bob")
+
+	team := &models.Team{Name: "backend", UserIDs: []uuid.UUID{alice.ID}}
+	if err := r.Teams.Create(ctx, team); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if team.ID == uuid.Nil || team.Version != 1 {
+		t.Fatalf("Create did not fill in ID and version 1: %+v", team)
+	}
+
+	got, err := r.Teams.FindByName(ctx, "backend")
+	if err != nil || got.ID != team.ID || !sameIDs(got.UserIDs, []uuid.UUID{alice.ID}) {
+		t.Errorf("FindByName = %+v, %v", got, err)
+	}
+	if got, err := r.Teams.FindByUserID(ctx, alice.ID); err != nil || got.ID != team.ID {
+		t.Errorf("FindByUserID = %+v, %v", got, err)
+	}
+
+	stale := *got
+	team.UserIDs = []uuid.UUID{alice.ID, bob.ID}
+	if err := r.Teams.Update(ctx, team); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if team.Version != 2 {
+		t.Errorf("Version after Update = %d, want 2", team.Version)
+	}
+	got, err = r.Teams.FindByID(ctx, team.ID)
+	if err != nil || !sameIDs(got.UserIDs, team.UserIDs) || got.Version != 2 {
+		t.Errorf("FindByID after Update = %+v, %v", got, err)
+	}
+
+	stale.Name = "lost update"
+	if err := r.Teams.Update(ctx, &stale); !errors.Is(err, repositories.ErrConcurrentModification) {
+		t.Errorf("Update with a stale version = %v, want ErrConcurrentModification", err)
+	}
+
+	other := &models.Team{Name: "frontend"}
+	if err := r.Teams.Create(ctx, other); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got, err := r.Teams.FindByID(ctx, other.ID); err != nil || got.UserIDs == nil || len(got.UserIDs) != 0 {
+		t.Errorf("FindByID of a team without members = %+v, %v; want empty UserIDs", got, err)
+	}
+	if err := r.Teams.Create(ctx, &models.Team{Name: "backend"}); !errors.Is(err, repositories.ErrDuplicateKey) {
+		t.Errorf("Create with taken name = %v, want ErrDuplicateKey", err)
+	}
+	other.Name = "backend"
+	if err := r.Teams.Update(ctx, other); !errors.Is(err, repositories.ErrDuplicateKey) {
+		t.Errorf("Update to taken name = %v, want ErrDuplicateKey", err)
+	}
+
+	if list, err := r.Teams.FindAll(ctx); err != nil || len(list) != 2 {
+		t.Errorf("FindAll = %d teams, %v; want 2", len(list), err)
+	}
+
+	if err := r.Teams.DeleteByID(ctx, team.ID); err != nil {
+		t.Fatalf("DeleteByID: %v", err)
+	}
+	checkNotFound(t, repositories.ErrTeamNotFound, map[string]error{
+		"FindByID":     second(r.Teams.FindByID(ctx, team.ID)),
+		"FindByName":   second(r.Teams.FindByName(ctx, "backend")),
+		"FindByUserID": second(r.Teams.FindByUserID(ctx, alice.ID)),
+		"Update":       r.Teams.Update(ctx, &models.Team{ID: team.ID, Name: "ghost", Version: team.Version}),
+		"DeleteByID":   r.Teams.DeleteByID(ctx, team.ID),
+	})
+}
+
+func testStatus(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	list, err := r.Statuses.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	var names []models.StatusName
+	for _, s := range list {
+		names = append(names, s.StatusName())
+
+		got, err := r.Statuses.FindByID(ctx, s.ID)
+		if err != nil || got.Name != s.Name {
+			t.Errorf("FindByID(%s) = %+v, %v", s.Name, got, err)
+		}
+	}
+	slices.Sort(names)
+	want := slices.Sorted(slices.Values(models.StatusNames))
+	if !slices.Equal(names, want) {
+		t.Errorf("FindAll = %v, want %v", names, want)
+	}
+
+	checkNotFound(t, repositories.ErrStatusNotFound, map[string]error{
+		"FindByID": second(r.Statuses.FindByID(ctx, uuid.New())),
+	})
+}
+
+func testPullRequest(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	author := createUser(t, r, "u1", "Alice")
+	bob := createUser(t, r, "u2", "Bob")
+	carol := createUser(t, r, "u3", "Carol")
+	dave := createUser(t, r, "u4", "Dave")
+
+	reviewer := bob.ID
+	pr := &models.PullRequest{
+		ExternalID:   "pr-1",
+		Title:        "Add contract tests",
+		AuthorID:     author.ID,
+		StatusID:     statusID(t, r, models.StatusOpen),
+		ReviewersIDs: []uuid.UUID{bob.ID, dave.ID},
+	}
+	pr.RecordEvent(models.AssignmentEvent{Type: models.EventAssign, ReviewerID: &reviewer, Actor: "system"})
+	if err := r.PullRequests.Create(ctx, pr); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if pr.ID == uuid.Nil || pr.Version != 1 || pr.AssignmentEvents != nil {
+		t.Fatalf("Create did not fill in ID and version 1 or kept events: %+v", pr)
+	}
+
+	got, err := r.PullRequests.FindByExternalID(ctx, "pr-1")
+	if err != nil || got.ID != pr.ID || got.Title != pr.Title || !slices.Equal(got.ReviewersIDs, pr.ReviewersIDs) {
+		t.Fatalf("FindByExternalID = %+v, %v", got, err)
+	}
+	if v := got.Review(bob.ID).Verdict; v != models.VerdictPending {
+		t.Errorf("verdict of a new reviewer = %s, want PENDING", v)
+	}
+
+	// Swap the first reviewer and record a verdict in one update. The
+	// replacement takes over the slot.
+	stale := *got
+	previous := bob.ID
+	replacement := carol.ID
+	got.ReviewersIDs = []uuid.UUID{carol.ID, dave.ID}
+	got.SetVerdict(carol.ID, models.VerdictApproved, time.Now())
+	got.RecordEvent(models.AssignmentEvent{Type: models.EventReassign, ReviewerID: &replacement, PreviousReviewerID: &previous, Actor: "system"})
+	if err := r.PullRequests.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got.Version != 2 {
+		t.Errorf("Version after Update = %d, want 2", got.Version)
+	}
+
+	got, err = r.PullRequests.FindByID(ctx, pr.ID)
+	if err != nil || !slices.Equal(got.ReviewersIDs, []uuid.UUID{carol.ID, dave.ID}) || got.Version != 2 {
+		t.Fatalf("FindByID after Update = %+v, %v", got, err)
+	}
+	if review := got.Review(carol.ID); review.Verdict != models.VerdictApproved || review.VerdictAt == nil {
+		t.Errorf("review after Update = %+v, want APPROVED with a time", review)
+	}
+
+	stale.Title = "lost update"
+	if err := r.PullRequests.Update(ctx, &stale); !errors.Is(err, repositories.ErrConcurrentModification) {
+		t.Errorf("Update with a stale version = %v, want ErrConcurrentModification", err)
+	}
+
+	events, err := r.PullRequests.FindAssignmentEvents(ctx, pr.ID)
+	if err != nil || len(events) != 2 {
+		t.Fatalf("FindAssignmentEvents = %d events, %v; want 2", len(events), err)
+	}
+	if e := events[1]; e.Type != models.EventReassign || *e.ReviewerID != carol.ID || *e.PreviousReviewerID != bob.ID || e.PullRequestID != pr.ID {
+		t.Errorf("second event = %+v", e)
+	}
+	if events[0].ID >= events[1].ID {
+		t.Errorf("event IDs %d, %d are not increasing", events[0].ID, events[1].ID)
+	}
+
+	duplicate := &models.PullRequest{ExternalID: "pr-1", Title: "again", AuthorID: author.ID, StatusID: pr.StatusID}
+	if err := r.PullRequests.Create(ctx, duplicate); !errors.Is(err, repositories.ErrDuplicateKey) {
+		t.Errorf("Create with taken external id = %v, want ErrDuplicateKey", err)
+	}
+
+	if err := r.PullRequests.DeleteByID(ctx, pr.ID); err != nil {
+		t.Fatalf("DeleteByID: %v", err)
+	}
+	checkNotFound(t, repositories.ErrPullRequestNotFound, map[string]error{
+		"FindByID":         second(r.PullRequests.FindByID(ctx, pr.ID)),
+		"FindByExternalID": second(r.PullRequests.FindByExternalID(ctx, "pr-1")),
+		"Update":           r.PullRequests.Update(ctx, &models.PullRequest{ID: pr.ID, Title: "ghost", AuthorID: author.ID, StatusID: pr.StatusID, Version: 2}),
+		"DeleteByID":       r.PullRequests.DeleteByID(ctx, pr.ID),
+	})
+}
+
+func testPullRequestQueries(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	alice := createUser(t, r, "u1", "Alice")
+	bob := createUser(t, r, "u2", "Bob")
+	carol := createUser(t, r, "u3", "Carol")
+
+	create := func(externalID string, author *models.User, status models.StatusName, reviewers ...uuid.UUID) *models.PullRequest {
+		t.Helper()
+		pr := &models.PullRequest{
+			ExternalID:   externalID,
+			Title:        externalID,
+			AuthorID:     author.ID,
+			StatusID:     statusID(t, r, status),
+			ReviewersIDs: reviewers,
+		}
+		if err := r.PullRequests.Create(ctx, pr); err != nil {
+			t.Fatalf("Create %s: %v", externalID, err)
+		}
+		// Keep creation times apart so the newest-first order is defined.
+		time.Sleep(2 * time.Millisecond)
+		return pr
+	}
+	open1 := create("pr-1", alice, models.StatusOpen, bob.ID, carol.ID)
+	open2 := create("pr-2", alice, models.StatusOpen, bob.ID)
+	draft := create("pr-3", carol, models.StatusDraft, bob.ID)
+	merged := create("pr-4", bob, models.StatusMerged, carol.ID)
+
+	tests := []struct {
+		name string
+		list func() ([]*models.PullRequest, error)
+		want []*models.PullRequest
+	}{
+		{"FindAll", func() ([]*models.PullRequest, error) { return r.PullRequests.FindAll(ctx) }, []*models.PullRequest{merged, draft, open2, open1}},
+		{"FindByAuthor", func() ([]*models.PullRequest, error) { return r.PullRequests.FindByAuthor(ctx, alice.ID) }, []*models.PullRequest{open2, open1}},
+		{"FindByReviewer", func() ([]*models.PullRequest, error) {
+			return r.PullRequests.FindByReviewer(ctx, bob.ID, repositories.PullRequestFilter{})
+		}, []*models.PullRequest{draft, open2, open1}},
+		{"FindByReviewer with statuses", func() ([]*models.PullRequest, error) {
+			return r.PullRequests.FindByReviewer(ctx, bob.ID, repositories.PullRequestFilter{Statuses: []models.StatusName{models.StatusDraft}})
+		}, []*models.PullRequest{draft}},
+		{"List by author", func() ([]*models.PullRequest, error) {
+			return r.PullRequests.List(ctx, repositories.PullRequestFilter{AuthorIDs: []uuid.UUID{bob.ID, carol.ID}})
+		}, []*models.PullRequest{merged, draft}},
+		{"List first page", func() ([]*models.PullRequest, error) {
+			return r.PullRequests.List(ctx, repositories.PullRequestFilter{Limit: 2})
+		}, []*models.PullRequest{merged, draft}},
+		{"List after cursor", func() ([]*models.PullRequest, error) {
+			return r.PullRequests.List(ctx, repositories.PullRequestFilter{
+				After: &repositories.PullRequestCursor{CreatedAt: draft.CreatedAt, ID: draft.ID},
+				Limit: 2,
+			})
+		}, []*models.PullRequest{open2, open1}},
+		{"List by reviewer without matches", func() ([]*models.PullRequest, error) {
+			return r.PullRequests.List(ctx, repositories.PullRequestFilter{ReviewerID: &alice.ID})
+		}, nil},
+	}
+	for _, tt := range tests {
+		list, err := tt.list()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got, want := externalIDs(list), externalIDs(tt.want); !slices.Equal(got, want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, want)
+		}
+	}
+
+	// Only OPEN pull requests count towards a reviewer's load.
+	counts, err := r.PullRequests.CountOpenReviews(ctx, []uuid.UUID{alice.ID, bob.ID, carol.ID})
+	if err != nil {
+		t.Fatalf("CountOpenReviews: %v", err)
+	}
+	if counts[alice.ID] != 0 || counts[bob.ID] != 2 || counts[carol.ID] != 1 {
+		t.Errorf("CountOpenReviews = %v, want bob 2 and carol 1", counts)
+	}
+}
+
+func createUser(t *testing.T, r Repositories, externalID string, username string) *models.User {
+	t.Helper()
+
+	u := &models.User{ExternalID: externalID, Username: username, IsActive: true}
+	if err := r.Users.Create(context.Background(), u); err != nil {
+		t.Fatalf("create user %s: %v", externalID, err)
+	}
+	return u
+}
+
+func statusID(t *testing.T, r Repositories, name models.StatusName) uuid.UUID {
+	t.Helper()
+
+	statuses, err := r.Statuses.FindAll(context.Background())
+	if err != nil {
+		t.Fatalf("find statuses: %v", err)
+	}
+	for _, s := range statuses {
+		if s.StatusName() == name {
+			return s.ID
+		}
+	}
+	t.Fatalf("status %s not found", name)
+	return uuid.Nil
+}
+
+func checkNotFound(t *testing.T, want error, errs map[string]error) {
+	t.Helper()
+
+	for name, err := range errs {
+		if !errors.Is(err, want) {
+			t.Errorf("%s = %v, want %v", name, err, want)
+		}
+	}
+}
+
+func second[T any](_ T, err error) error {
+	return err
+}
+
+// sameIDs compares a and b ignoring order.
+func sameIDs(a, b []uuid.UUID) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	compare := func(x, y uuid.UUID) int { return bytes.Compare(x[:], y[:]) }
+	slices.SortFunc(a, compare)
+	slices.SortFunc(b, compare)
+	return slices.Equal(a, b)
+}
+
+func externalIDs(list []*models.PullRequest) []string {
+	ids := make([]string, 0, len(list))
+	for _, pr := range list {
+		ids = append(ids, pr.ExternalID)
+	}
+	return ids
+}
@@ -0,0 +1,66 @@
+package dtos
+
+type CodeHostEventResponseDTO struct {
+	Status string          `json:"status"`
+	Reason string          `json:"reason,omitempty"`
+	Pr     *PullRequestDTO `json:"pr,omitempty"`
+}
+
+type UserIdentityDTO struct {
+	UserID   string `json:"user_id"`
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+}
+
+type UserIdentityResponseDTO struct {
+	Identity UserIdentityDTO `json:"identity"`
+}
+
+type GitHubUserDTO struct {
+	Login string `json:"login"`
+}
+
+// GitHubPullRequestEventDTO is the subset of GitHub's pull_request webhook
+// payload the service reads.
+type GitHubPullRequestEventDTO struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Number int           `json:"number"`
+		Title  string        `json:"title"`
+		Draft  bool          `json:"draft"`
+		Merged bool          `json:"merged"`
+		User   GitHubUserDTO `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender GitHubUserDTO `json:"sender"`
+}
+
+type GitLabUserDTO struct {
+	Username string `json:"username"`
+}
+
+// GitLabMergeRequestEventDTO is the subset of GitLab's Merge Request Hook
+// payload the service reads.
+type GitLabMergeRequestEventDTO struct {
+	ObjectKind string        `json:"object_kind"`
+	User       GitLabUserDTO `json:"user"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
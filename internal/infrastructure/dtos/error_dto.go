@@ -0,0 +1,11 @@
+package dtos
+
+type ErrorDTO struct {
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+type ErrorResponseDTO struct {
+	Error ErrorDTO `json:"error"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+type HealthCheckDTO struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type PoolStatsDTO struct {
+	TotalConns           int32 `json:"total_conns"`
+	IdleConns            int32 `json:"idle_conns"`
+	AcquiredConns        int32 `json:"acquired_conns"`
+	ConstructingConns    int32 `json:"constructing_conns"`
+	MaxConns             int32 `json:"max_conns"`
+	AcquireCount         int64 `json:"acquire_count"`
+	EmptyAcquireCount    int64 `json:"empty_acquire_count"`
+	CanceledAcquireCount int64 `json:"canceled_acquire_count"`
+	AcquireDurationMs    int64 `json:"acquire_duration_ms"`
+}
+
+type HealthResponseDTO struct {
+	Status string                    `json:"status"`
+	Checks map[string]HealthCheckDTO `json:"checks,omitempty"`
+	Pool   *PoolStatsDTO             `json:"pool,omitempty"`
+}
@@ -1,29 +1,104 @@
 package dtos
 
-import (
-	"time"
-
-	"github.com/google/uuid"
-)
+import "time"
 
 type PullRequestDTO struct {
-	PullRequestID     uuid.UUID   `json:"pull_request_id"`
+	PullRequestID     string      `json:"pull_request_id"`
 	PullRequestName   string      `json:"pull_request_name"`
-	AuthorID          uuid.UUID   `json:"author_id"`
+	AuthorID          string      `json:"author_id"`
 	Status            string      `json:"status"`
-	AssignedReviewers []uuid.UUID `json:"assigned_reviewers"`
+	AssignedReviewers []string    `json:"assigned_reviewers"`
+	Reviews           []ReviewDTO `json:"reviews,omitempty"`
 	CreatedAt         *time.Time  `json:"createdAt,omitempty"`
 	MergedAt          *time.Time  `json:"mergedAt,omitempty"`
+	// Version is sent as the ETag header rather than in the body.
+	Version int64 `json:"-"`
+}
+
+type ReviewDTO struct {
+	ReviewerID string     `json:"reviewer_id"`
+	Verdict    string     `json:"verdict"`
+	VerdictAt  *time.Time `json:"verdictAt,omitempty"`
 }
 
 type PullRequestShortDTO struct {
-	PullRequestID   uuid.UUID `json:"pull_request_id"`
-	PullRequestName string    `json:"pull_request_name"`
-	AuthorID        uuid.UUID `json:"author_id"`
-	Status          string    `json:"status"`
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	Status          string `json:"status"`
 }
 
 type ReassignReviewerResponseDTO struct {
 	Pr         PullRequestDTO `json:"pr"`
-	ReplacedBy uuid.UUID      `json:"replaced_by"`
+	ReplacedBy string         `json:"replaced_by"`
+}
+
+type PullRequestCreateRequestDTO struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	Draft           bool   `json:"draft,omitempty"`
+}
+
+type PullRequestMergeRequestDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type PullRequestStatusChangeRequestDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+type PullRequestReassignRequestDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type PullRequestReviewRequestDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Verdict       string `json:"verdict"`
+}
+
+type PullRequestResponseDTO struct {
+	Pr PullRequestDTO `json:"pr"`
+}
+
+// PullRequestListQueryDTO carries the raw /pullRequest/list query parameters.
+type PullRequestListQueryDTO struct {
+	Statuses    []string
+	AuthorID    string
+	ReviewerID  string
+	TeamName    string
+	CreatedFrom string
+	CreatedTo   string
+	MergedFrom  string
+	MergedTo    string
+	Limit       string
+	Cursor      string
+}
+
+type PullRequestListResponseDTO struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	NextCursor   string           `json:"next_cursor,omitempty"`
+}
+
+type ReviewersChangedEventDTO struct {
+	PullRequestID string   `json:"pull_request_id"`
+	Added         []string `json:"added"`
+	Removed       []string `json:"removed"`
+}
+
+type AssignmentEventDTO struct {
+	Type               string    `json:"type"`
+	ReviewerID         string    `json:"reviewer_id,omitempty"`
+	PreviousReviewerID string    `json:"previous_reviewer_id,omitempty"`
+	Actor              string    `json:"actor,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+type PullRequestHistoryDTO struct {
+	PullRequestID string               `json:"pull_request_id"`
+	Events        []AssignmentEventDTO `json:"events"`
 }
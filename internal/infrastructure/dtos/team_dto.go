@@ -1,14 +1,26 @@
 package dtos
 
-import "github.com/google/uuid"
-
 type TeamMemberDTO struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Username string    `json:"username"`
-	IsActive bool      `json:"is_active"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
 }
 
 type TeamDTO struct {
 	TeamName string          `json:"team_name"`
 	Members  []TeamMemberDTO `json:"members"`
+	// Version is sent as the ETag header rather than in the body.
+	Version int64 `json:"-"`
+}
+
+type TeamAddResponseDTO struct {
+	Team TeamDTO `json:"team"`
+}
+
+type TeamSettingsDTO struct {
+	TeamName          string `json:"team_name"`
+	ReviewerCount     int    `json:"reviewer_count"`
+	MinReviewers      int    `json:"min_reviewers"`
+	RequiredApprovals int    `json:"required_approvals"`
+	SelectionStrategy string `json:"selection_strategy"`
 }
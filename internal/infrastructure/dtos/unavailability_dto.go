@@ -0,0 +1,62 @@
+package dtos
+
+import "time"
+
+type UnavailabilityDTO struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	Reason    string    `json:"reason"`
+	SourceID  string    `json:"source_id,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type UnavailabilityCreateRequestDTO struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+type UnavailabilityDeleteRequestDTO struct {
+	ID string `json:"id"`
+}
+
+type UnavailabilityResponseDTO struct {
+	Unavailability UnavailabilityDTO `json:"unavailability"`
+}
+
+type UnavailabilityListResponseDTO struct {
+	UserID         string              `json:"user_id"`
+	Unavailability []UnavailabilityDTO `json:"unavailability"`
+}
+
+type CalendarSourceDTO struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	URL          string     `json:"url,omitempty"`
+	LastSyncedAt *time.Time `json:"lastSyncedAt,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type CalendarSourceCreateRequestDTO struct {
+	UserID string `json:"user_id"`
+	URL    string `json:"url"`
+}
+
+type CalendarSourceDeleteRequestDTO struct {
+	ID string `json:"id"`
+}
+
+type CalendarImportResponseDTO struct {
+	Source   CalendarSourceDTO `json:"source"`
+	Imported int               `json:"imported"`
+	Skipped  []string          `json:"skipped,omitempty"`
+}
+
+type CalendarSourceListResponseDTO struct {
+	UserID  string              `json:"user_id"`
+	Sources []CalendarSourceDTO `json:"sources"`
+}
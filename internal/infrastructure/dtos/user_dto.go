@@ -1,20 +1,39 @@
 package dtos
 
-import "github.com/google/uuid"
-
 type UserDTO struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Username string    `json:"username"`
-	TeamName string    `json:"team_name"`
-	IsActive bool      `json:"is_active"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
 }
 
 type UserSetActiveRequestDTO struct {
-	UserID   uuid.UUID `json:"user_id"`
-	IsActive bool      `json:"is_active"`
+	UserID              string `json:"user_id"`
+	IsActive            bool   `json:"is_active"`
+	ReassignOpenReviews bool   `json:"reassign_open_reviews,omitempty"`
 }
 
 type UserGetReviewResponseDTO struct {
-	UserID       uuid.UUID             `json:"user_id"`
+	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
 }
+
+type UserSetActiveResponseDTO struct {
+	User         UserDTO                `json:"user"`
+	Reassignment *ReassignmentReportDTO `json:"reassignment,omitempty"`
+}
+
+type ReassignmentReportDTO struct {
+	Reassigned    []ReassignedReviewDTO    `json:"reassigned"`
+	NotReassigned []NotReassignedReviewDTO `json:"not_reassigned"`
+}
+
+type ReassignedReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReplacedBy    string `json:"replaced_by"`
+}
+
+type NotReassignedReviewDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	Reason        string `json:"reason"`
+}
@@ -0,0 +1,29 @@
+package dtos
+
+import "time"
+
+type WebhookDTO struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type WebhookCreateRequestDTO struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+type WebhookDeleteRequestDTO struct {
+	ID string `json:"id"`
+}
+
+type WebhookResponseDTO struct {
+	Webhook WebhookDTO `json:"webhook"`
+}
+
+type WebhookListResponseDTO struct {
+	Webhooks []WebhookDTO `json:"webhooks"`
+}
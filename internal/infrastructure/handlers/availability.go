@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/infrastructure/calendar"
+	"pullrequest-manager/internal/infrastructure/dtos"
+)
+
+type AvailabilityHandler struct {
+	service *services.DefaultAvailabilityService
+}
+
+func NewAvailabilityHandler(service *services.DefaultAvailabilityService) *AvailabilityHandler {
+	return &AvailabilityHandler{service: service}
+}
+
+func (h *AvailabilityHandler) AddUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req dtos.UnavailabilityCreateRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.UserID == "" {
+		writeBadRequest(w, errors.New("user_id is required"))
+		return
+	}
+
+	period, err := h.service.AddUnavailability(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, dtos.UnavailabilityResponseDTO{Unavailability: *period})
+}
+
+func (h *AvailabilityHandler) ListUnavailability(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeBadRequest(w, errors.New("user_id query parameter is required"))
+		return
+	}
+
+	periods, err := h.service.ListUnavailability(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, periods)
+}
+
+func (h *AvailabilityHandler) DeleteUnavailability(w http.ResponseWriter, r *http.Request) {
+	var req dtos.UnavailabilityDeleteRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.ID == "" {
+		writeBadRequest(w, errors.New("id is required"))
+		return
+	}
+
+	if err := h.service.DeleteUnavailability(r.Context(), req.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AvailabilityHandler) AddCalendarSource(w http.ResponseWriter, r *http.Request) {
+	var req dtos.CalendarSourceCreateRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.UserID == "" || req.URL == "" {
+		writeBadRequest(w, errors.New("user_id and url are required"))
+		return
+	}
+
+	result, err := h.service.AddCalendarSource(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, result)
+}
+
+// ImportCalendar accepts an .ics file either as a multipart/form-data "file"
+// field or as the raw request body. user_id comes from the query string or,
+// for multipart requests, from a form field.
+func (h *AvailabilityHandler) ImportCalendar(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, calendar.MaxFeedSize+maxRequestBodySize)
+	userID := r.URL.Query().Get("user_id")
+
+	var data []byte
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(calendar.MaxFeedSize); err != nil {
+			writeBadRequest(w, fmt.Errorf("parse multipart form: %w", err))
+			return
+		}
+		if userID == "" {
+			userID = r.FormValue("user_id")
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeBadRequest(w, errors.New("file field is required"))
+			return
+		}
+		defer file.Close()
+		data, err = io.ReadAll(io.LimitReader(file, calendar.MaxFeedSize+1))
+		if err != nil {
+			writeBadRequest(w, fmt.Errorf("read file: %w", err))
+			return
+		}
+	} else {
+		var err error
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			writeBadRequest(w, fmt.Errorf("read request body: %w", err))
+			return
+		}
+	}
+
+	if userID == "" {
+		writeBadRequest(w, errors.New("user_id is required"))
+		return
+	}
+	if len(data) > calendar.MaxFeedSize {
+		writeBadRequest(w, fmt.Errorf("calendar exceeds %d bytes", calendar.MaxFeedSize))
+		return
+	}
+
+	result, err := h.service.ImportCalendar(r.Context(), userID, data)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *AvailabilityHandler) ListCalendarSources(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeBadRequest(w, errors.New("user_id query parameter is required"))
+		return
+	}
+
+	sources, err := h.service.ListCalendarSources(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sources)
+}
+
+func (h *AvailabilityHandler) DeleteCalendarSource(w http.ResponseWriter, r *http.Request) {
+	var req dtos.CalendarSourceDeleteRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.ID == "" {
+		writeBadRequest(w, errors.New("id is required"))
+		return
+	}
+
+	if err := h.service.DeleteCalendarSource(r.Context(), req.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"strings"
+)
+
+// maxWebhookBodySize leaves room for large code host payloads, which embed
+// full repository and user objects.
+const maxWebhookBodySize = 5 << 20
+
+const (
+	githubEventHeader     = "X-GitHub-Event"
+	githubSignatureHeader = "X-Hub-Signature-256"
+
+	gitlabEventHeader = "X-Gitlab-Event"
+	gitlabTokenHeader = "X-Gitlab-Token"
+
+	gitlabMergeRequestEvent = "Merge Request Hook"
+)
+
+var ErrInvalidSignature = errors.New("webhook signature or token is invalid")
+
+type CodeHostSecrets struct {
+	GitHub string
+	GitLab string
+}
+
+// CodeHostHandler receives pull request webhooks from code hosts. A provider
+// whose secret is empty rejects every delivery.
+type CodeHostHandler struct {
+	service *services.DefaultCodeHostService
+	secrets CodeHostSecrets
+}
+
+func NewCodeHostHandler(service *services.DefaultCodeHostService, secrets CodeHostSecrets) *CodeHostHandler {
+	return &CodeHostHandler{service: service, secrets: secrets}
+}
+
+func (h *CodeHostHandler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		writeBadRequest(w, fmt.Errorf("read request body: %w", err))
+		return
+	}
+	if !verifyGitHubSignature(h.secrets.GitHub, body, r.Header.Get(githubSignatureHeader)) {
+		writeError(w, ErrInvalidSignature)
+		return
+	}
+
+	switch event := r.Header.Get(githubEventHeader); event {
+	case "ping":
+		writeCodeHostResponse(w, &dtos.CodeHostEventResponseDTO{Status: services.CodeHostStatusIgnored, Reason: "pong"})
+		return
+	case "pull_request":
+	default:
+		writeCodeHostResponse(w, &dtos.CodeHostEventResponseDTO{
+			Status: services.CodeHostStatusIgnored,
+			Reason: fmt.Sprintf("event %q is not handled", event),
+		})
+		return
+	}
+
+	var payload dtos.GitHubPullRequestEventDTO
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeBadRequest(w, fmt.Errorf("decode pull_request payload: %w", err))
+		return
+	}
+	if payload.Repository.FullName == "" || payload.PullRequest.Number == 0 {
+		writeBadRequest(w, errors.New("repository.full_name and pull_request.number are required"))
+		return
+	}
+
+	ctx := r.Context()
+	if payload.Sender.Login != "" {
+		ctx = services.WithActor(ctx, models.ProviderGitHub+":"+payload.Sender.Login)
+	}
+
+	resp, err := h.service.HandlePullRequestEvent(ctx, services.CodeHostPullRequestEvent{
+		Provider:      models.ProviderGitHub,
+		Action:        githubAction(&payload),
+		PullRequestID: fmt.Sprintf("%s#%d", payload.Repository.FullName, payload.PullRequest.Number),
+		Title:         payload.PullRequest.Title,
+		AuthorLogin:   payload.PullRequest.User.Login,
+		Draft:         payload.PullRequest.Draft,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeCodeHostResponse(w, resp)
+}
+
+func (h *CodeHostHandler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	if !verifyGitLabToken(h.secrets.GitLab, r.Header.Get(gitlabTokenHeader)) {
+		writeError(w, ErrInvalidSignature)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		writeBadRequest(w, fmt.Errorf("read request body: %w", err))
+		return
+	}
+
+	if event := r.Header.Get(gitlabEventHeader); event != gitlabMergeRequestEvent {
+		writeCodeHostResponse(w, &dtos.CodeHostEventResponseDTO{
+			Status: services.CodeHostStatusIgnored,
+			Reason: fmt.Sprintf("event %q is not handled", event),
+		})
+		return
+	}
+
+	var payload dtos.GitLabMergeRequestEventDTO
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeBadRequest(w, fmt.Errorf("decode merge request payload: %w", err))
+		return
+	}
+	if payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID == 0 {
+		writeBadRequest(w, errors.New("project.path_with_namespace and object_attributes.iid are required"))
+		return
+	}
+
+	ctx := r.Context()
+	if payload.User.Username != "" {
+		ctx = services.WithActor(ctx, models.ProviderGitLab+":"+payload.User.Username)
+	}
+
+	// Merge Request Hooks carry only the author's numeric ID; the user who
+	// triggered "open" is the author.
+	resp, err := h.service.HandlePullRequestEvent(ctx, services.CodeHostPullRequestEvent{
+		Provider:      models.ProviderGitLab,
+		Action:        gitlabAction(&payload),
+		PullRequestID: fmt.Sprintf("%s!%d", payload.Project.PathWithNamespace, payload.ObjectAttributes.IID),
+		Title:         payload.ObjectAttributes.Title,
+		AuthorLogin:   payload.User.Username,
+		Draft:         payload.ObjectAttributes.Draft || payload.ObjectAttributes.WorkInProgress,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeCodeHostResponse(w, resp)
+}
+
+func (h *CodeHostHandler) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	var req dtos.UserIdentityDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.UserID == "" || req.Provider == "" || req.Login == "" {
+		writeBadRequest(w, errors.New("user_id, provider and login are required"))
+		return
+	}
+
+	identity, err := h.service.LinkIdentity(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.UserIdentityResponseDTO{Identity: *identity})
+}
+
+// writeCodeHostResponse answers ignored events with 202: the delivery was
+// valid but changed nothing. Code hosts only flag hooks that get a 4xx or 5xx,
+// which is kept for bad signatures and malformed payloads.
+func writeCodeHostResponse(w http.ResponseWriter, resp *dtos.CodeHostEventResponseDTO) {
+	status := http.StatusOK
+	if resp.Status == services.CodeHostStatusIgnored {
+		status = http.StatusAccepted
+	}
+	writeJSON(w, status, resp)
+}
+
+// githubAction maps GitHub's action names; "closed" covers both merged and
+// abandoned pull requests.
+func githubAction(payload *dtos.GitHubPullRequestEventDTO) services.CodeHostAction {
+	switch payload.Action {
+	case "opened":
+		return services.CodeHostActionOpened
+	case "closed":
+		if payload.PullRequest.Merged {
+			return services.CodeHostActionMerged
+		}
+		return services.CodeHostActionClosed
+	case "reopened":
+		return services.CodeHostActionReopened
+	case "ready_for_review":
+		return services.CodeHostActionReadyForReview
+	default:
+		return services.CodeHostAction(payload.Action)
+	}
+}
+
+// gitlabAction maps GitLab's action names. Marking a draft as ready arrives
+// as an "update" whose changes flip draft off.
+func gitlabAction(payload *dtos.GitLabMergeRequestEventDTO) services.CodeHostAction {
+	switch payload.ObjectAttributes.Action {
+	case "open":
+		return services.CodeHostActionOpened
+	case "merge":
+		return services.CodeHostActionMerged
+	case "close":
+		return services.CodeHostActionClosed
+	case "reopen":
+		return services.CodeHostActionReopened
+	case "update":
+		if d := payload.Changes.Draft; d != nil && d.Previous && !d.Current {
+			return services.CodeHostActionReadyForReview
+		}
+	}
+	return services.CodeHostAction(payload.ObjectAttributes.Action)
+}
+
+// verifyGitLabToken compares the shared secret GitLab sends verbatim.
+func verifyGitLabToken(secret string, token string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
+
+func verifyGitHubSignature(secret string, body []byte, header string) bool {
+	if secret == "" {
+		return false
+	}
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), got)
+}
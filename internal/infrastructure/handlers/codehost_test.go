@@ -0,0 +1,280 @@
+package handlers_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/handlers"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readFixture returns a webhook payload recorded from a code host.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func signGitHub(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// gitHubDelivery sends body the way GitHub does, signed with the test secret.
+func gitHubDelivery(t *testing.T, router http.Handler, event string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	return do(t, router, http.MethodPost, "/integrations/github/webhook", body,
+		"X-GitHub-Event", event,
+		"X-Hub-Signature-256", signGitHub(gitHubSecret, body),
+	)
+}
+
+// gitLabDelivery sends body the way GitLab does, with the test token.
+func gitLabDelivery(t *testing.T, router http.Handler, event string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	return do(t, router, http.MethodPost, "/integrations/gitlab/webhook", body,
+		"X-Gitlab-Event", event,
+		"X-Gitlab-Token", gitLabToken,
+	)
+}
+
+// newCodeHostRouter sets up a team whose pull requests need an approval to
+// merge and links u1 to the given code host login.
+func newCodeHostRouter(t *testing.T, provider, login string) http.Handler {
+	t.Helper()
+
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	decode[dtos.TeamSettingsDTO](t, do(t, router, http.MethodPost, "/team/settings", dtos.TeamSettingsDTO{
+		TeamName:          "backend",
+		ReviewerCount:     2,
+		MinReviewers:      1,
+		RequiredApprovals: 1,
+	}), http.StatusOK)
+	decode[dtos.UserIdentityResponseDTO](t, do(t, router, http.MethodPost, "/users/linkIdentity",
+		dtos.UserIdentityDTO{UserID: "u1", Provider: provider, Login: login}), http.StatusOK)
+	return router
+}
+
+// checkExternalMerge checks that prID is merged and that its history records
+// the merge as done on the code host by actor.
+func checkExternalMerge(t *testing.T, router http.Handler, resp dtos.CodeHostEventResponseDTO, prID, actor string) {
+	t.Helper()
+
+	if resp.Status != services.CodeHostStatusApplied || resp.Pr == nil || resp.Pr.Status != "MERGED" || resp.Pr.MergedAt == nil {
+		t.Fatalf("merge response = %+v, want %s merged", resp, prID)
+	}
+
+	history := decode[dtos.PullRequestHistoryDTO](t, do(t, router, http.MethodGet, "/pullRequest/history?pull_request_id="+prID, nil), http.StatusOK)
+	i := slices.IndexFunc(history.Events, func(e dtos.AssignmentEventDTO) bool { return e.Type == "MERGE" })
+	if i < 0 {
+		t.Fatalf("history = %+v, want a merge event", history.Events)
+	}
+	if e := history.Events[i]; e.Actor != actor || e.Reason != "merged on code host" {
+		t.Errorf("merge event = %+v, want actor %s", e, actor)
+	}
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	router := newCodeHostRouter(t, "github", "octocat")
+	const prID = "octo-org/hello-world#42"
+
+	opened := readFixture(t, "github/pull_request_opened.json")
+	rec := gitHubDelivery(t, router, "pull_request", opened)
+	resp := decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusOK)
+	if resp.Status != services.CodeHostStatusApplied || resp.Pr == nil {
+		t.Fatalf("opened response = %+v, want the created PR", resp)
+	}
+	pr := resp.Pr
+	if pr.PullRequestID != prID || pr.PullRequestName != "Add full-text search to the catalog" || pr.AuthorID != "u1" || pr.Status != "OPEN" {
+		t.Errorf("pr = %+v, want %s opened by u1", pr, prID)
+	}
+	slices.Sort(pr.AssignedReviewers)
+	if !slices.Equal(pr.AssignedReviewers, []string{"u2", "u3"}) {
+		t.Errorf("reviewers = %v, want u2 and u3", pr.AssignedReviewers)
+	}
+
+	// GitHub redelivers on timeouts; the second delivery changes nothing.
+	rec = gitHubDelivery(t, router, "pull_request", opened)
+	if resp := decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusAccepted); resp.Status != services.CodeHostStatusIgnored {
+		t.Errorf("redelivery = %+v, want ignored", resp)
+	}
+
+	// The merge happened on GitHub, so the approval gate does not apply.
+	merged := readFixture(t, "github/pull_request_closed_merged.json")
+	rec = gitHubDelivery(t, router, "pull_request", merged)
+	checkExternalMerge(t, router, decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusOK), prID, "github:hubot")
+
+	// Closing a merged pull request is a transition it cannot make.
+	closed := []byte(strings.Replace(string(merged), `"merged": true`, `"merged": false`, 1))
+	rec = gitHubDelivery(t, router, "pull_request", closed)
+	if resp := decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusAccepted); resp.Status != services.CodeHostStatusIgnored {
+		t.Errorf("close after merge = %+v, want ignored", resp)
+	}
+}
+
+func TestGitHubWebhookIgnoredEvents(t *testing.T) {
+	router := newCodeHostRouter(t, "github", "octocat")
+	addTeam(t, router, "solo", "u9")
+	decode[dtos.UserIdentityResponseDTO](t, do(t, router, http.MethodPost, "/users/linkIdentity",
+		dtos.UserIdentityDTO{UserID: "u9", Provider: "github", Login: "loner"}), http.StatusOK)
+	opened := string(readFixture(t, "github/pull_request_opened.json"))
+
+	tests := []struct {
+		name   string
+		event  string
+		body   []byte
+		reason string
+	}{
+		{"ping", "ping", readFixture(t, "github/ping.json"), "pong"},
+		{"other event", "push", []byte(`{"ref":"refs/heads/main"}`), `"push" is not handled`},
+		{"other action", "pull_request", []byte(strings.Replace(opened, `"action": "opened"`, `"action": "labeled"`, 1)), `"labeled" is not handled`},
+		{"untracked merge", "pull_request", []byte(strings.ReplaceAll(string(readFixture(t, "github/pull_request_closed_merged.json")), "42", "43")), "not tracked"},
+		// Without a link the author's login must match a user ID.
+		{"unlinked author", "pull_request", []byte(strings.ReplaceAll(opened, "Octocat", "stranger")), "not linked"},
+		{"no candidates", "pull_request", []byte(strings.ReplaceAll(opened, "Octocat", "loner")), "no users available"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := gitHubDelivery(t, router, tt.event, tt.body)
+			resp := decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusAccepted)
+			if resp.Status != services.CodeHostStatusIgnored || !strings.Contains(resp.Reason, tt.reason) {
+				t.Errorf("response = %+v, want ignored with %q", resp, tt.reason)
+			}
+		})
+	}
+
+	list := decode[dtos.PullRequestListResponseDTO](t, do(t, router, http.MethodGet, "/pullRequest/list", nil), http.StatusOK)
+	if len(list.PullRequests) != 0 {
+		t.Errorf("pull requests = %+v, want none", list.PullRequests)
+	}
+}
+
+func TestGitHubWebhookRejected(t *testing.T) {
+	router := newCodeHostRouter(t, "github", "octocat")
+	opened := readFixture(t, "github/pull_request_opened.json")
+	tampered := []byte(strings.Replace(string(opened), "Add full-text search", "Drop all tables", 1))
+
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		status    int
+		code      string
+	}{
+		{"no signature", opened, "", http.StatusUnauthorized, handlers.CodeUnauthorized},
+		{"other secret", opened, signGitHub("other", opened), http.StatusUnauthorized, handlers.CodeUnauthorized},
+		{"tampered body", tampered, signGitHub(gitHubSecret, opened), http.StatusUnauthorized, handlers.CodeUnauthorized},
+		{"bare hex", opened, strings.TrimPrefix(signGitHub(gitHubSecret, opened), "sha256="), http.StatusUnauthorized, handlers.CodeUnauthorized},
+		{"malformed JSON", []byte(`{"action":`), signGitHub(gitHubSecret, []byte(`{"action":`)), http.StatusBadRequest, handlers.CodeBadRequest},
+		{"no repository", []byte(`{"action":"opened","pull_request":{"number":1}}`), signGitHub(gitHubSecret, []byte(`{"action":"opened","pull_request":{"number":1}}`)), http.StatusBadRequest, handlers.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, router, http.MethodPost, "/integrations/github/webhook", tt.body,
+				"X-GitHub-Event", "pull_request",
+				"X-Hub-Signature-256", tt.signature,
+			)
+			checkError(t, rec, tt.status, tt.code)
+		})
+	}
+}
+
+func TestGitLabWebhook(t *testing.T) {
+	router := newCodeHostRouter(t, "gitlab", "ada.l")
+	const prID = "shop/backend/catalog!17"
+
+	rec := gitLabDelivery(t, router, "Merge Request Hook", readFixture(t, "gitlab/merge_request_open.json"))
+	resp := decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusOK)
+	if resp.Status != services.CodeHostStatusApplied || resp.Pr == nil {
+		t.Fatalf("open response = %+v, want the created PR", resp)
+	}
+	if pr := resp.Pr; pr.PullRequestID != prID || pr.AuthorID != "u1" || pr.Status != "DRAFT" || len(pr.AssignedReviewers) != 2 {
+		t.Errorf("pr = %+v, want draft %s by u1 with two reviewers", pr, prID)
+	}
+
+	// Marking the draft ready arrives as an update that flips draft off.
+	rec = gitLabDelivery(t, router, "Merge Request Hook", readFixture(t, "gitlab/merge_request_update_ready.json"))
+	if resp := decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusOK); resp.Pr == nil || resp.Pr.Status != "OPEN" {
+		t.Errorf("ready response = %+v, want the PR open", resp)
+	}
+
+	// The merge happened on GitLab, so the approval gate does not apply.
+	merge := readFixture(t, "gitlab/merge_request_merge.json")
+	rec = gitLabDelivery(t, router, "Merge Request Hook", merge)
+	checkExternalMerge(t, router, decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusOK), prID, "gitlab:ghopper")
+
+	// Reopening a merged merge request is a transition it cannot make.
+	reopen := []byte(strings.Replace(string(merge), `"action": "merge"`, `"action": "reopen"`, 1))
+	rec = gitLabDelivery(t, router, "Merge Request Hook", reopen)
+	if resp := decode[dtos.CodeHostEventResponseDTO](t, rec, http.StatusAccepted); resp.Status != services.CodeHostStatusIgnored {
+		t.Errorf("reopen after merge = %+v, want ignored", resp)
+	}
+}
+
+func TestGitLabWebhookIgnoredEvents(t *testing.T) {
+	router := newCodeHostRouter(t, "gitlab", "ada.l")
+	addTeam(t, router, "solo", "u9")
+	decode[dtos.UserIdentityResponseDTO](t, do(t, router, http.MethodPost, "/users/linkIdentity",
+		dtos.UserIdentityDTO{UserID: "u9", Provider: "gitlab", Login: "loner"}), http.StatusOK)
+	open := string(readFixture(t, "gitlab/merge_request_open.json"))
+
+	tests := []struct {
+		name   string
+		event  string
+		body   string
+		reason string
+	}{
+		{"other event", "Push Hook", `{"object_kind":"push","ref":"refs/heads/main"}`, `"Push Hook" is not handled`},
+		{"other action", "Merge Request Hook", strings.Replace(open, `"action": "open"`, `"action": "approved"`, 1), `"approved" is not handled`},
+		{"plain update", "Merge Request Hook", strings.Replace(open, `"action": "open"`, `"action": "update"`, 1), `"update" is not handled`},
+		{"untracked merge", "Merge Request Hook", strings.Replace(string(readFixture(t, "gitlab/merge_request_merge.json")), `"iid": 17`, `"iid": 18`, 1), "not tracked"},
+		// Without a link the user's username must match a user ID.
+		{"unlinked author", "Merge Request Hook", strings.ReplaceAll(open, `"username": "ada.l"`, `"username": "stranger"`), "not linked"},
+		{"no candidates", "Merge Request Hook", strings.ReplaceAll(open, `"username": "ada.l"`, `"username": "loner"`), "no users available"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := decode[dtos.CodeHostEventResponseDTO](t, gitLabDelivery(t, router, tt.event, []byte(tt.body)), http.StatusAccepted)
+			if resp.Status != services.CodeHostStatusIgnored || !strings.Contains(resp.Reason, tt.reason) {
+				t.Errorf("response = %+v, want ignored with %q", resp, tt.reason)
+			}
+		})
+	}
+
+	list := decode[dtos.PullRequestListResponseDTO](t, do(t, router, http.MethodGet, "/pullRequest/list", nil), http.StatusOK)
+	if len(list.PullRequests) != 0 {
+		t.Errorf("pull requests = %+v, want none", list.PullRequests)
+	}
+}
+
+func TestGitLabWebhookRejected(t *testing.T) {
+	router := newCodeHostRouter(t, "gitlab", "ada.l")
+	open := readFixture(t, "gitlab/merge_request_open.json")
+
+	for _, token := range []string{"", "other-token", gitLabToken + " "} {
+		rec := do(t, router, http.MethodPost, "/integrations/gitlab/webhook", open,
+			"X-Gitlab-Event", "Merge Request Hook",
+			"X-Gitlab-Token", token,
+		)
+		checkError(t, rec, http.StatusUnauthorized, handlers.CodeUnauthorized)
+	}
+
+	checkError(t, gitLabDelivery(t, router, "Merge Request Hook", []byte(`{"object_kind":`)), http.StatusBadRequest, handlers.CodeBadRequest)
+	checkError(t, gitLabDelivery(t, router, "Merge Request Hook", []byte(`{"object_attributes":{"iid":1,"action":"open"}}`)), http.StatusBadRequest, handlers.CodeBadRequest)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CodeTeamExists        = "TEAM_EXISTS"
+	CodePRExists          = "PR_EXISTS"
+	CodePRMerged          = "PR_MERGED"
+	CodePRClosed          = "PR_CLOSED"
+	CodeInvalidTransition = "INVALID_TRANSITION"
+	CodePRNotOpen         = "PR_NOT_OPEN"
+	CodeNotApproved       = "NOT_APPROVED"
+	CodeNotAssigned       = "NOT_ASSIGNED"
+	CodeNoCandidate       = "NO_CANDIDATE"
+	CodeNotFound          = "NOT_FOUND"
+	CodeBadRequest        = "BAD_REQUEST"
+	CodeUnauthorized      = "UNAUTHORIZED"
+	CodeConcurrentUpdate  = "CONCURRENT_MODIFICATION"
+	CodeVersionMismatch   = "PRECONDITION_FAILED"
+	CodeAlreadyExists     = "ALREADY_EXISTS"
+	CodeKeyReused         = "IDEMPOTENCY_KEY_REUSED"
+	CodeKeyInProgress     = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	CodeFeedUnreachable   = "CALENDAR_UNREACHABLE"
+	CodeInternalError     = "INTERNAL_ERROR"
+)
+
+const correlationIDHeader = "X-Correlation-ID"
+
+// errorMapping pairs a sentinel error with its HTTP status and ErrorResponse
+// code. Clients only see the sentinel's message, never the wrapped chain,
+// unless withDetails is set for validation errors built from request data.
+type errorMapping struct {
+	err         error
+	status      int
+	code        string
+	withDetails bool
+}
+
+var errorMappings = []errorMapping{
+	{services.ErrAuthorNotFound, http.StatusNotFound, CodeNotFound, false},
+	{services.ErrTeamNotFound, http.StatusNotFound, CodeNotFound, false},
+	{services.ErrPRNotFound, http.StatusNotFound, CodeNotFound, false},
+	{services.ErrUserNotFound, http.StatusNotFound, CodeNotFound, false},
+	{repositories.ErrPullRequestNotFound, http.StatusNotFound, CodeNotFound, false},
+	{repositories.ErrTeamNotFound, http.StatusNotFound, CodeNotFound, false},
+	{repositories.ErrUserNotFound, http.StatusNotFound, CodeNotFound, false},
+	{repositories.ErrStatusNotFound, http.StatusNotFound, CodeNotFound, false},
+	{services.ErrWebhookNotFound, http.StatusNotFound, CodeNotFound, false},
+	{services.ErrUnavailabilityNotFound, http.StatusNotFound, CodeNotFound, false},
+	{services.ErrCalendarSourceNotFound, http.StatusNotFound, CodeNotFound, false},
+
+	{services.ErrTeamAlreadyExists, http.StatusBadRequest, CodeTeamExists, false},
+	{services.ErrPRAlreadyExists, http.StatusConflict, CodePRExists, false},
+	{repositories.ErrDuplicateKey, http.StatusConflict, CodeAlreadyExists, false},
+	{services.ErrPRAlreadyMerged, http.StatusConflict, CodePRMerged, false},
+	{services.ErrPRClosed, http.StatusConflict, CodePRClosed, false},
+	{models.ErrInvalidStatusTransition, http.StatusConflict, CodeInvalidTransition, true},
+	{services.ErrPRNotOpen, http.StatusConflict, CodePRNotOpen, false},
+	{services.ErrPRNotApproved, http.StatusConflict, CodeNotApproved, true},
+	{services.ErrUserNotReviewer, http.StatusConflict, CodeNotAssigned, false},
+	{services.ErrNoReviewCandidates, http.StatusConflict, CodeNoCandidate, false},
+	{repositories.ErrConcurrentModification, http.StatusConflict, CodeConcurrentUpdate, false},
+
+	{services.ErrIdempotencyKeyInProgress, http.StatusConflict, CodeKeyInProgress, false},
+
+	{services.ErrPreconditionFailed, http.StatusPreconditionFailed, CodeVersionMismatch, true},
+	{services.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, CodeKeyReused, false},
+	{services.ErrCalendarFetchFailed, http.StatusUnprocessableEntity, CodeFeedUnreachable, true},
+
+	{services.ErrInvalidTeamSettings, http.StatusBadRequest, CodeBadRequest, true},
+	{services.ErrInvalidVerdict, http.StatusBadRequest, CodeBadRequest, true},
+	{services.ErrInvalidWebhook, http.StatusBadRequest, CodeBadRequest, true},
+	{services.ErrInvalidIdentity, http.StatusBadRequest, CodeBadRequest, true},
+	{services.ErrInvalidListQuery, http.StatusBadRequest, CodeBadRequest, true},
+	{services.ErrInvalidUnavailability, http.StatusBadRequest, CodeBadRequest, true},
+	{services.ErrInvalidCalendar, http.StatusBadRequest, CodeBadRequest, true},
+
+	{ErrInvalidSignature, http.StatusUnauthorized, CodeUnauthorized, false},
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			message := m.err.Error()
+			if m.withDetails {
+				message = err.Error()
+			}
+			writeErrorResponse(w, m.status, dtos.ErrorDTO{Code: m.code, Message: message})
+			return
+		}
+	}
+
+	correlationID := uuid.NewString()
+	log.Printf("internal error [correlation_id=%s]: %v", correlationID, err)
+
+	w.Header().Set(correlationIDHeader, correlationID)
+	writeErrorResponse(w, http.StatusInternalServerError, dtos.ErrorDTO{
+		Code:          CodeInternalError,
+		Message:       "internal server error",
+		CorrelationID: correlationID,
+	})
+}
+
+func writeBadRequest(w http.ResponseWriter, err error) {
+	writeErrorResponse(w, http.StatusBadRequest, dtos.ErrorDTO{Code: CodeBadRequest, Message: err.Error()})
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, e dtos.ErrorDTO) {
+	writeJSON(w, status, dtos.ErrorResponseDTO{Error: e})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"testing"
+)
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) dtos.ErrorDTO {
+	t.Helper()
+
+	var resp dtos.ErrorResponseDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error response %s: %v", rec.Body, err)
+	}
+	return resp.Error
+}
+
+// TestWriteErrorMappings covers every mapped error, including the ones the
+// in-memory repositories never produce, such as concurrent modification.
+func TestWriteErrorMappings(t *testing.T) {
+	for _, m := range errorMappings {
+		t.Run(m.err.Error(), func(t *testing.T) {
+			err := fmt.Errorf("handle request: %w", fmt.Errorf("%w: detail from the request", m.err))
+
+			rec := httptest.NewRecorder()
+			writeError(rec, err)
+
+			if rec.Code != m.status {
+				t.Errorf("status = %d, want %d", rec.Code, m.status)
+			}
+			got := decodeError(t, rec)
+			if got.Code != m.code {
+				t.Errorf("code = %q, want %q", got.Code, m.code)
+			}
+			want := m.err.Error()
+			if m.withDetails {
+				want = err.Error()
+			}
+			if got.Message != want {
+				t.Errorf("message = %q, want %q", got.Message, want)
+			}
+			if got.CorrelationID != "" || rec.Header().Get(correlationIDHeader) != "" {
+				t.Error("mapped error has a correlation ID")
+			}
+		})
+	}
+}
+
+func TestWriteErrorUnmapped(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeError(rec, errors.New("connect to db at 10.0.0.5: password authentication failed"))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	got := decodeError(t, rec)
+	if got.Code != CodeInternalError || got.Message != "internal server error" {
+		t.Errorf("error = %+v, want a generic internal error", got)
+	}
+	if got.CorrelationID == "" || rec.Header().Get(correlationIDHeader) != got.CorrelationID {
+		t.Errorf("correlation ID %q in body, %q in header, want the same non-empty ID", got.CorrelationID, rec.Header().Get(correlationIDHeader))
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+
+	readinessTimeout = 2 * time.Second
+)
+
+// HealthHandler serves liveness and readiness probes. The pool is nil when the
+// service runs on in-memory storage, in which case database checks are skipped.
+type HealthHandler struct {
+	pool       *pgxpool.Pool
+	statusRepo repositories.Status
+}
+
+func NewHealthHandler(pool *pgxpool.Pool, statusRepo repositories.Status) *HealthHandler {
+	return &HealthHandler{pool: pool, statusRepo: statusRepo}
+}
+
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, dtos.HealthResponseDTO{Status: healthStatusOK})
+}
+
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	resp := dtos.HealthResponseDTO{
+		Status: healthStatusOK,
+		Checks: map[string]dtos.HealthCheckDTO{
+			"statuses": checkResult(h.checkStatuses(ctx)),
+		},
+	}
+	if h.pool != nil {
+		resp.Checks["database"] = checkResult(h.pool.Ping(ctx))
+		resp.Pool = poolStats(h.pool.Stat())
+	}
+
+	status := http.StatusOK
+	for _, check := range resp.Checks {
+		if check.Status != healthStatusOK {
+			resp.Status = healthStatusFail
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	writeJSON(w, status, resp)
+}
+
+func (h *HealthHandler) checkStatuses(ctx context.Context) error {
+	statuses, err := h.statusRepo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("list statuses: %w", err)
+	}
+
+	present := make(map[string]bool, len(statuses))
+	for _, st := range statuses {
+		present[st.Name] = true
+	}
+	for _, name := range models.StatusNames {
+		if !present[string(name)] {
+			return fmt.Errorf("status %q is missing", name)
+		}
+	}
+
+	return nil
+}
+
+func checkResult(err error) dtos.HealthCheckDTO {
+	if err != nil {
+		return dtos.HealthCheckDTO{Status: healthStatusFail, Error: err.Error()}
+	}
+	return dtos.HealthCheckDTO{Status: healthStatusOK}
+}
+
+func poolStats(stat *pgxpool.Stat) *dtos.PoolStatsDTO {
+	return &dtos.PoolStatsDTO{
+		TotalConns:           stat.TotalConns(),
+		IdleConns:            stat.IdleConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		ConstructingConns:    stat.ConstructingConns(),
+		MaxConns:             stat.MaxConns(),
+		AcquireCount:         stat.AcquireCount(),
+		EmptyAcquireCount:    stat.EmptyAcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDurationMs:    stat.AcquireDuration().Milliseconds(),
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader    = "Idempotency-Key"
+	idempotentReplayHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 255
+
+	idempotencyStoreTimeout = 5 * time.Second
+)
+
+// replayedHeaders are stored with the response and restored on replay.
+var replayedHeaders = []string{"Content-Type", etagHeader}
+
+// responseRecorder passes the response through while keeping a copy.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// withIdempotency makes next safe to retry with the same Idempotency-Key.
+// Requests without the header are passed through. Server errors release the
+// key so that a retry runs the request again.
+func withIdempotency(svc *services.DefaultIdempotencyService, scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			writeBadRequest(w, errors.New("idempotency key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+
+		stored, err := svc.Begin(r.Context(), scope, key, hex.EncodeToString(sum[:]))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if stored != nil {
+			for name, value := range stored.ResponseHeaders {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set(idempotentReplayHeader, "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.ResponseBody)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next(rec, r)
+
+		// The request context may already be cancelled if the client gave up,
+		// which is exactly when the outcome must still be recorded.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), idempotencyStoreTimeout)
+		defer cancel()
+
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+			if err := svc.Release(ctx, scope, key); err != nil {
+				log.Printf("release idempotency key %q: %v", key, err)
+			}
+			return
+		}
+
+		headers := make(map[string]string, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if v := rec.Header().Get(name); v != "" {
+				headers[name] = v
+			}
+		}
+		if err := svc.Complete(ctx, scope, key, rec.status, headers, rec.body.Bytes()); err != nil {
+			log.Printf("store response for idempotency key %q (status %d): %v", key, rec.status, err)
+		}
+	}
+}
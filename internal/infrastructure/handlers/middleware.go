@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	actorHeader    = "X-Actor"
+	maxActorLength = 128
+
+	ifMatchHeader = "If-Match"
+	etagHeader    = "ETag"
+)
+
+// withActor exposes the caller-supplied X-Actor header to the services for
+// audit records. The header is informational and is not authenticated.
+func withActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get(actorHeader))
+		if utf8.RuneCountInString(actor) > maxActorLength {
+			actor = string([]rune(actor)[:maxActorLength])
+		}
+		if actor != "" {
+			r = r.WithContext(services.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withIfMatch turns an If-Match header into an expected version for the PR or
+// team the request modifies. "*" matches any version; a value that is not one
+// of our ETags can never match.
+func withIfMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifMatch := strings.TrimSpace(r.Header.Get(ifMatchHeader))
+		if ifMatch != "" && ifMatch != "*" {
+			version, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 64)
+			if err != nil || !strings.HasPrefix(ifMatch, `"`) || !strings.HasSuffix(ifMatch, `"`) {
+				version = -1
+			}
+			r = r.WithContext(services.WithExpectedVersion(r.Context(), version))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func setETag(w http.ResponseWriter, version int64) {
+	if version > 0 {
+		w.Header().Set(etagHeader, `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"strings"
+)
+
+func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
+	var req dtos.PullRequestCreateRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.PullRequestID == "" || req.PullRequestName == "" || req.AuthorID == "" {
+		writeBadRequest(w, errors.New("pull_request_id, pull_request_name and author_id are required"))
+		return
+	}
+
+	create := h.service.CreateWithReviewers
+	if req.Draft {
+		create = h.service.CreateDraftWithReviewers
+	}
+
+	pr, err := create(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setETag(w, pr.Version)
+	writeJSON(w, http.StatusCreated, dtos.PullRequestResponseDTO{Pr: *pr})
+}
+
+func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
+	var req dtos.PullRequestMergeRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.PullRequestID == "" {
+		writeBadRequest(w, errors.New("pull_request_id is required"))
+		return
+	}
+
+	pr, err := h.service.MarkAsMerged(r.Context(), req.PullRequestID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setETag(w, pr.Version)
+	writeJSON(w, http.StatusOK, dtos.PullRequestResponseDTO{Pr: *pr})
+}
+
+func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
+	var req dtos.PullRequestReassignRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.PullRequestID == "" || req.OldUserID == "" {
+		writeBadRequest(w, errors.New("pull_request_id and old_user_id are required"))
+		return
+	}
+
+	resp, err := h.service.ReassignReviewer(r.Context(), req.OldUserID, req.PullRequestID, req.Reason)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setETag(w, resp.Pr.Version)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) GetPullRequestHistory(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeBadRequest(w, errors.New("pull_request_id query parameter is required"))
+		return
+	}
+
+	history, err := h.service.GetPullRequestHistory(r.Context(), prID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
+}
+
+func (h *Handler) ListPullRequests(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var statuses []string
+	for _, v := range q["status"] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				statuses = append(statuses, s)
+			}
+		}
+	}
+
+	list, err := h.service.ListPullRequests(r.Context(), dtos.PullRequestListQueryDTO{
+		Statuses:    statuses,
+		AuthorID:    q.Get("author_id"),
+		ReviewerID:  q.Get("reviewer_id"),
+		TeamName:    q.Get("team_name"),
+		CreatedFrom: q.Get("created_from"),
+		CreatedTo:   q.Get("created_to"),
+		MergedFrom:  q.Get("merged_from"),
+		MergedTo:    q.Get("merged_to"),
+		Limit:       q.Get("limit"),
+		Cursor:      q.Get("cursor"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (h *Handler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req dtos.PullRequestReviewRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.PullRequestID == "" || req.UserID == "" || req.Verdict == "" {
+		writeBadRequest(w, errors.New("pull_request_id, user_id and verdict are required"))
+		return
+	}
+
+	pr, err := h.service.SubmitReview(r.Context(), req.PullRequestID, req.UserID, models.ReviewVerdict(req.Verdict))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setETag(w, pr.Version)
+	writeJSON(w, http.StatusOK, dtos.PullRequestResponseDTO{Pr: *pr})
+}
+
+func (h *Handler) ClosePullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.ClosePullRequest)
+}
+
+func (h *Handler) ReopenPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.ReopenPullRequest)
+}
+
+func (h *Handler) PublishPullRequest(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.PublishPullRequest)
+}
+
+func (h *Handler) changeStatus(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ctx context.Context, prID string) (*dtos.PullRequestDTO, error),
+) {
+	var req dtos.PullRequestStatusChangeRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.PullRequestID == "" {
+		writeBadRequest(w, errors.New("pull_request_id is required"))
+		return
+	}
+
+	pr, err := change(r.Context(), req.PullRequestID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setETag(w, pr.Version)
+	writeJSON(w, http.StatusOK, dtos.PullRequestResponseDTO{Pr: *pr})
+}
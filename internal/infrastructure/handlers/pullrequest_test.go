@@ -0,0 +1,278 @@
+package handlers_test
+
+import (
+	"net/http"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/handlers"
+	"slices"
+	"testing"
+)
+
+type errorCase struct {
+	name   string
+	body   any
+	header []string
+	status int
+	code   string
+}
+
+func checkErrors(t *testing.T, router http.Handler, target string, tests []errorCase) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, do(t, router, http.MethodPost, target, tt.body, tt.header...), tt.status, tt.code)
+		})
+	}
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	addTeam(t, router, "solo", "s1")
+
+	rec := do(t, router, http.MethodPost, "/pullRequest/create", dtos.PullRequestCreateRequestDTO{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "u1",
+	})
+	pr := decode[dtos.PullRequestResponseDTO](t, rec, http.StatusCreated).Pr
+	if pr.PullRequestID != "pr-1" || pr.AuthorID != "u1" || pr.Status != "OPEN" || pr.CreatedAt == nil {
+		t.Errorf("pr = %+v, want open pr-1 by u1", pr)
+	}
+	slices.Sort(pr.AssignedReviewers)
+	if !slices.Equal(pr.AssignedReviewers, []string{"u2", "u3"}) {
+		t.Errorf("reviewers = %v, want the rest of the team", pr.AssignedReviewers)
+	}
+	if etag := rec.Header().Get("ETag"); etag == "" {
+		t.Error("no ETag")
+	}
+
+	rec = do(t, router, http.MethodPost, "/pullRequest/create", dtos.PullRequestCreateRequestDTO{
+		PullRequestID:   "pr-2",
+		PullRequestName: "Draft",
+		AuthorID:        "u2",
+		Draft:           true,
+	})
+	if pr := decode[dtos.PullRequestResponseDTO](t, rec, http.StatusCreated).Pr; pr.Status != "DRAFT" {
+		t.Errorf("draft status = %s, want DRAFT", pr.Status)
+	}
+
+	checkErrors(t, router, "/pullRequest/create", []errorCase{
+		{"malformed JSON", `{"pull_request_id":`, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"missing fields", dtos.PullRequestCreateRequestDTO{PullRequestID: "pr-3"}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"duplicate", dtos.PullRequestCreateRequestDTO{PullRequestID: "pr-1", PullRequestName: "Again", AuthorID: "u1"}, nil, http.StatusConflict, handlers.CodePRExists},
+		{"unknown author", dtos.PullRequestCreateRequestDTO{PullRequestID: "pr-3", PullRequestName: "x", AuthorID: "nobody"}, nil, http.StatusNotFound, handlers.CodeNotFound},
+		{"no candidates", dtos.PullRequestCreateRequestDTO{PullRequestID: "pr-3", PullRequestName: "x", AuthorID: "s1"}, nil, http.StatusConflict, handlers.CodeNoCandidate},
+	})
+}
+
+func TestMergePullRequest(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	createPR(t, router, "pr-1", "u1")
+
+	rec := do(t, router, http.MethodPost, "/pullRequest/merge", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-1"})
+	pr := decode[dtos.PullRequestResponseDTO](t, rec, http.StatusOK).Pr
+	if pr.Status != "MERGED" || pr.MergedAt == nil {
+		t.Errorf("pr = %+v, want merged with mergedAt", pr)
+	}
+
+	// Merging again is a no-op that reports the original merge time.
+	rec = do(t, router, http.MethodPost, "/pullRequest/merge", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-1"})
+	if again := decode[dtos.PullRequestResponseDTO](t, rec, http.StatusOK).Pr; again.MergedAt == nil || !again.MergedAt.Equal(*pr.MergedAt) {
+		t.Errorf("second merge = %+v, want mergedAt %v", again, pr.MergedAt)
+	}
+
+	createPR(t, router, "pr-closed", "u1")
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/close", dtos.PullRequestStatusChangeRequestDTO{PullRequestID: "pr-closed"}), http.StatusOK)
+
+	decode[dtos.TeamSettingsDTO](t, do(t, router, http.MethodPost, "/team/settings", dtos.TeamSettingsDTO{
+		TeamName:          "backend",
+		ReviewerCount:     2,
+		MinReviewers:      1,
+		RequiredApprovals: 1,
+	}), http.StatusOK)
+	gated := createPR(t, router, "pr-gated", "u1")
+
+	checkErrors(t, router, "/pullRequest/merge", []errorCase{
+		{"malformed JSON", `{`, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"missing ID", dtos.PullRequestMergeRequestDTO{}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"unknown PR", dtos.PullRequestMergeRequestDTO{PullRequestID: "nope"}, nil, http.StatusNotFound, handlers.CodeNotFound},
+		{"closed", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-closed"}, nil, http.StatusConflict, handlers.CodeInvalidTransition},
+		{"not approved", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-gated"}, nil, http.StatusConflict, handlers.CodeNotApproved},
+		{"stale If-Match", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-gated"}, []string{"If-Match", `"99"`}, http.StatusPreconditionFailed, handlers.CodeVersionMismatch},
+	})
+
+	// An approval from an assigned reviewer opens the gate.
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/review",
+		dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-gated", UserID: gated.AssignedReviewers[0], Verdict: "APPROVED"}), http.StatusOK)
+	rec = do(t, router, http.MethodPost, "/pullRequest/merge", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-gated"})
+	if pr := decode[dtos.PullRequestResponseDTO](t, rec, http.StatusOK).Pr; pr.Status != "MERGED" {
+		t.Errorf("approved pr status = %s, want MERGED", pr.Status)
+	}
+}
+
+func TestReassignReviewer(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3", "u4")
+	pr := createPR(t, router, "pr-1", "u1")
+	old := pr.AssignedReviewers[0]
+
+	rec := do(t, router, http.MethodPost, "/pullRequest/reassign", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-1", OldUserID: old, Reason: "on leave"})
+	resp := decode[dtos.ReassignReviewerResponseDTO](t, rec, http.StatusOK)
+	if resp.ReplacedBy == "" || resp.ReplacedBy == old || slices.Contains(resp.Pr.AssignedReviewers, old) || !slices.Contains(resp.Pr.AssignedReviewers, resp.ReplacedBy) {
+		t.Errorf("reassign = %+v, want %s replaced", resp, old)
+	}
+
+	current := resp.Pr.AssignedReviewers[0]
+	createPR(t, router, "pr-merged", "u1")
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/merge", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-merged"}), http.StatusOK)
+	closed := createPR(t, router, "pr-closed", "u1")
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/close", dtos.PullRequestStatusChangeRequestDTO{PullRequestID: "pr-closed"}), http.StatusOK)
+
+	// The only team member left to take over is the one just unassigned.
+	decode[dtos.UserSetActiveResponseDTO](t, do(t, router, http.MethodPost, "/users/setIsActive", dtos.UserSetActiveRequestDTO{UserID: old}), http.StatusOK)
+
+	checkErrors(t, router, "/pullRequest/reassign", []errorCase{
+		{"malformed JSON", `[]`, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"missing fields", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-1"}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"unknown PR", dtos.PullRequestReassignRequestDTO{PullRequestID: "nope", OldUserID: current}, nil, http.StatusNotFound, handlers.CodeNotFound},
+		{"unknown user", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-1", OldUserID: "nobody"}, nil, http.StatusNotFound, handlers.CodeNotFound},
+		{"not assigned", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-1", OldUserID: "u1"}, nil, http.StatusConflict, handlers.CodeNotAssigned},
+		{"no candidate", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-1", OldUserID: current}, nil, http.StatusConflict, handlers.CodeNoCandidate},
+		{"merged", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-merged", OldUserID: current}, nil, http.StatusConflict, handlers.CodePRMerged},
+		{"closed", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-closed", OldUserID: closed.AssignedReviewers[0]}, nil, http.StatusConflict, handlers.CodePRClosed},
+		{"stale If-Match", dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-1", OldUserID: current}, []string{"If-Match", `"99"`}, http.StatusPreconditionFailed, handlers.CodeVersionMismatch},
+	})
+}
+
+func TestSubmitReview(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	pr := createPR(t, router, "pr-1", "u1")
+	reviewer := pr.AssignedReviewers[0]
+
+	rec := do(t, router, http.MethodPost, "/pullRequest/review", dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-1", UserID: reviewer, Verdict: "APPROVED"})
+	got := decode[dtos.PullRequestResponseDTO](t, rec, http.StatusOK).Pr
+	i := slices.IndexFunc(got.Reviews, func(r dtos.ReviewDTO) bool { return r.ReviewerID == reviewer })
+	if i < 0 || got.Reviews[i].Verdict != "APPROVED" || got.Reviews[i].VerdictAt == nil {
+		t.Errorf("reviews = %+v, want %s approved", got.Reviews, reviewer)
+	}
+
+	createPR(t, router, "pr-closed", "u1")
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/close", dtos.PullRequestStatusChangeRequestDTO{PullRequestID: "pr-closed"}), http.StatusOK)
+	createPR(t, router, "pr-merged", "u1")
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/merge", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-merged"}), http.StatusOK)
+
+	checkErrors(t, router, "/pullRequest/review", []errorCase{
+		{"missing fields", dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-1"}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"invalid verdict", dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-1", UserID: reviewer, Verdict: "PENDING"}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"unknown PR", dtos.PullRequestReviewRequestDTO{PullRequestID: "nope", UserID: reviewer, Verdict: "APPROVED"}, nil, http.StatusNotFound, handlers.CodeNotFound},
+		{"unknown user", dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-1", UserID: "nobody", Verdict: "APPROVED"}, nil, http.StatusNotFound, handlers.CodeNotFound},
+		{"not assigned", dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-1", UserID: "u1", Verdict: "APPROVED"}, nil, http.StatusConflict, handlers.CodeNotAssigned},
+		{"closed", dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-closed", UserID: reviewer, Verdict: "APPROVED"}, nil, http.StatusConflict, handlers.CodePRNotOpen},
+		{"merged", dtos.PullRequestReviewRequestDTO{PullRequestID: "pr-merged", UserID: reviewer, Verdict: "APPROVED"}, nil, http.StatusConflict, handlers.CodePRMerged},
+	})
+}
+
+func TestPullRequestLifecycle(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	createPR(t, router, "pr-1", "u1")
+
+	steps := []struct {
+		target string
+		status string
+	}{
+		{"/pullRequest/close", "CLOSED"},
+		{"/pullRequest/reopen", "OPEN"},
+		{"/pullRequest/reopen", "OPEN"},
+		{"/pullRequest/publish", "OPEN"},
+	}
+	for _, step := range steps {
+		rec := do(t, router, http.MethodPost, step.target, dtos.PullRequestStatusChangeRequestDTO{PullRequestID: "pr-1"})
+		if pr := decode[dtos.PullRequestResponseDTO](t, rec, http.StatusOK).Pr; pr.Status != step.status {
+			t.Errorf("%s: status = %s, want %s", step.target, pr.Status, step.status)
+		}
+	}
+
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/merge", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-1"}), http.StatusOK)
+	for _, target := range []string{"/pullRequest/close", "/pullRequest/reopen", "/pullRequest/publish"} {
+		checkErrors(t, router, target, []errorCase{
+			{"missing ID", dtos.PullRequestStatusChangeRequestDTO{}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+			{"unknown PR", dtos.PullRequestStatusChangeRequestDTO{PullRequestID: "nope"}, nil, http.StatusNotFound, handlers.CodeNotFound},
+			{"merged", dtos.PullRequestStatusChangeRequestDTO{PullRequestID: "pr-1"}, nil, http.StatusConflict, handlers.CodeInvalidTransition},
+		})
+	}
+}
+
+func TestPullRequestHistory(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3", "u4")
+	pr := createPR(t, router, "pr-1", "u1")
+	decode[dtos.ReassignReviewerResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/reassign",
+		dtos.PullRequestReassignRequestDTO{PullRequestID: "pr-1", OldUserID: pr.AssignedReviewers[0]}, "X-Actor", "lead"), http.StatusOK)
+
+	history := decode[dtos.PullRequestHistoryDTO](t, do(t, router, http.MethodGet, "/pullRequest/history?pull_request_id=pr-1", nil), http.StatusOK)
+	var types []string
+	for _, e := range history.Events {
+		types = append(types, e.Type)
+	}
+	if !slices.Equal(types, []string{"ASSIGN", "ASSIGN", "REASSIGN"}) {
+		t.Fatalf("event types = %v, want two assignments and a reassignment", types)
+	}
+	if last := history.Events[2]; last.PreviousReviewerID != pr.AssignedReviewers[0] || last.Actor != "lead" {
+		t.Errorf("reassign event = %+v", last)
+	}
+
+	checkError(t, do(t, router, http.MethodGet, "/pullRequest/history", nil), http.StatusBadRequest, handlers.CodeBadRequest)
+	checkError(t, do(t, router, http.MethodGet, "/pullRequest/history?pull_request_id=nope", nil), http.StatusNotFound, handlers.CodeNotFound)
+}
+
+func TestListPullRequests(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	createPR(t, router, "pr-1", "u1")
+	createPR(t, router, "pr-2", "u2")
+	createPR(t, router, "pr-3", "u1")
+	decode[dtos.PullRequestResponseDTO](t, do(t, router, http.MethodPost, "/pullRequest/merge", dtos.PullRequestMergeRequestDTO{PullRequestID: "pr-3"}), http.StatusOK)
+
+	list := decode[dtos.PullRequestListResponseDTO](t, do(t, router, http.MethodGet, "/pullRequest/list?status=OPEN&author_id=u1", nil), http.StatusOK)
+	if len(list.PullRequests) != 1 || list.PullRequests[0].PullRequestID != "pr-1" {
+		t.Errorf("list = %+v, want only pr-1", list.PullRequests)
+	}
+
+	page := decode[dtos.PullRequestListResponseDTO](t, do(t, router, http.MethodGet, "/pullRequest/list?limit=2", nil), http.StatusOK)
+	if len(page.PullRequests) != 2 || page.NextCursor == "" {
+		t.Fatalf("first page = %+v, want two PRs and a cursor", page)
+	}
+	rest := decode[dtos.PullRequestListResponseDTO](t, do(t, router, http.MethodGet, "/pullRequest/list?limit=2&cursor="+page.NextCursor, nil), http.StatusOK)
+	if len(rest.PullRequests) != 1 || rest.NextCursor != "" {
+		t.Errorf("second page = %+v, want the last PR", rest)
+	}
+
+	for _, query := range []string{"status=UNKNOWN", "limit=0", "limit=x", "cursor=garbage", "created_from=yesterday"} {
+		checkError(t, do(t, router, http.MethodGet, "/pullRequest/list?"+query, nil), http.StatusBadRequest, handlers.CodeBadRequest)
+	}
+}
+
+func TestCreatePullRequestIdempotency(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	body := dtos.PullRequestCreateRequestDTO{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"}
+
+	first := do(t, router, http.MethodPost, "/pullRequest/create", body, "Idempotency-Key", "k1")
+	want := decode[dtos.PullRequestResponseDTO](t, first, http.StatusCreated).Pr
+
+	replay := do(t, router, http.MethodPost, "/pullRequest/create", body, "Idempotency-Key", "k1")
+	if got := decode[dtos.PullRequestResponseDTO](t, replay, http.StatusCreated).Pr; !slices.Equal(got.AssignedReviewers, want.AssignedReviewers) {
+		t.Errorf("replayed reviewers = %v, want %v", got.AssignedReviewers, want.AssignedReviewers)
+	}
+	if replay.Header().Get("Idempotent-Replayed") != "true" || replay.Header().Get("ETag") != first.Header().Get("ETag") {
+		t.Errorf("replay headers = %v", replay.Header())
+	}
+
+	body.PullRequestName = "Something else"
+	checkError(t, do(t, router, http.MethodPost, "/pullRequest/create", body, "Idempotency-Key", "k1"), http.StatusUnprocessableEntity, handlers.CodeKeyReused)
+}
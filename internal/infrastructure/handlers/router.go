@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+)
+
+func NewRouter(
+	h *Handler,
+	health *HealthHandler,
+	webhooks *WebhookHandler,
+	codeHosts *CodeHostHandler,
+	availability *AvailabilityHandler,
+	idempotency *services.DefaultIdempotencyService,
+) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /health", health.Live)
+	mux.HandleFunc("GET /ready", health.Ready)
+
+	mux.HandleFunc("POST /team/add", h.AddTeam)
+	mux.HandleFunc("GET /team/get", h.GetTeam)
+	mux.HandleFunc("GET /team/settings", h.GetTeamSettings)
+	mux.HandleFunc("POST /team/settings", h.UpdateTeamSettings)
+
+	mux.HandleFunc("POST /users/setIsActive", h.SetUserActive)
+	mux.HandleFunc("GET /users/getReview", h.GetUserReviews)
+	mux.HandleFunc("POST /users/linkIdentity", codeHosts.LinkIdentity)
+	mux.HandleFunc("POST /users/unavailability/add", availability.AddUnavailability)
+	mux.HandleFunc("GET /users/unavailability/list", availability.ListUnavailability)
+	mux.HandleFunc("POST /users/unavailability/delete", availability.DeleteUnavailability)
+	mux.HandleFunc("POST /users/calendar/add", availability.AddCalendarSource)
+	mux.HandleFunc("POST /users/calendar/import", availability.ImportCalendar)
+	mux.HandleFunc("GET /users/calendar/list", availability.ListCalendarSources)
+	mux.HandleFunc("POST /users/calendar/delete", availability.DeleteCalendarSource)
+
+	mux.HandleFunc("POST /pullRequest/create", withIdempotency(idempotency, "pullRequest/create", h.CreatePullRequest))
+	mux.HandleFunc("POST /pullRequest/merge", h.MergePullRequest)
+	mux.HandleFunc("POST /pullRequest/reassign", withIdempotency(idempotency, "pullRequest/reassign", h.ReassignReviewer))
+	mux.HandleFunc("POST /pullRequest/review", h.SubmitReview)
+	mux.HandleFunc("GET /pullRequest/history", h.GetPullRequestHistory)
+	mux.HandleFunc("GET /pullRequest/list", h.ListPullRequests)
+	mux.HandleFunc("POST /pullRequest/close", h.ClosePullRequest)
+	mux.HandleFunc("POST /pullRequest/reopen", h.ReopenPullRequest)
+	mux.HandleFunc("POST /pullRequest/publish", h.PublishPullRequest)
+
+	mux.HandleFunc("POST /webhooks/add", webhooks.AddWebhook)
+	mux.HandleFunc("GET /webhooks/list", webhooks.ListWebhooks)
+	mux.HandleFunc("POST /webhooks/delete", webhooks.DeleteWebhook)
+
+	mux.HandleFunc("POST /integrations/github/webhook", codeHosts.GitHubWebhook)
+	mux.HandleFunc("POST /integrations/gitlab/webhook", codeHosts.GitLabWebhook)
+
+	return withActor(withIfMatch(mux))
+}
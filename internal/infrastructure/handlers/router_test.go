@@ -0,0 +1,152 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/infrastructure/calendar"
+	"pullrequest-manager/internal/infrastructure/database/memory"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/handlers"
+	"testing"
+)
+
+const (
+	gitHubSecret = "github-secret"
+	gitLabToken  = "gitlab-token"
+)
+
+// newTestRouter wires the router to fresh in-memory repositories the same way
+// main does for -storage=memory. Reviewers are picked round-robin so the
+// assignments are predictable.
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+
+	statusRepo := memory.NewStatusRepository()
+	outboxRepo := memory.NewOutboxRepository()
+	userRepo := memory.NewUserRepository()
+	identityRepo := memory.NewUserIdentityRepository()
+	unavailable := memory.NewUnavailabilityRepository()
+	txManager := memory.NewTxManager()
+
+	prService, err := services.NewDefaultPullRequestService(
+		userRepo,
+		memory.NewPullRequestRepository(statusRepo, outboxRepo),
+		memory.NewTeamRepository(),
+		statusRepo,
+		memory.NewTeamSettingsRepository(),
+		services.NewRoundRobinSelector(),
+		txManager,
+		unavailable,
+	)
+	if err != nil {
+		t.Fatalf("create pull request service: %v", err)
+	}
+
+	return handlers.NewRouter(
+		handlers.NewHandler(prService),
+		handlers.NewHealthHandler(nil, statusRepo),
+		handlers.NewWebhookHandler(services.NewDefaultWebhookService(memory.NewWebhookSubscriptionRepository())),
+		handlers.NewCodeHostHandler(
+			services.NewDefaultCodeHostService(prService, userRepo, identityRepo),
+			handlers.CodeHostSecrets{GitHub: gitHubSecret, GitLab: gitLabToken},
+		),
+		handlers.NewAvailabilityHandler(services.NewDefaultAvailabilityService(
+			userRepo,
+			unavailable,
+			memory.NewCalendarSourceRepository(),
+			txManager,
+			calendar.NewHTTPFetcher(nil),
+		)),
+		services.NewDefaultIdempotencyService(memory.NewIdempotencyRepository(), services.DefaultIdempotencyTTL),
+	)
+}
+
+// do sends a request with body encoded as JSON; a string or []byte body is
+// sent as is. header lists alternating names and values.
+func do(t *testing.T, router http.Handler, method, target string, body any, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var raw []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		raw = []byte(b)
+	case []byte:
+		raw = b
+	default:
+		var err error
+		if raw, err = json.Marshal(b); err != nil {
+			t.Fatalf("encode request body: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(method, target, bytes.NewReader(raw))
+	if raw != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode fails the test unless the response has the wanted status, then
+// decodes its body into a T.
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder, status int) T {
+	t.Helper()
+
+	var v T
+	if rec.Code != status {
+		t.Fatalf("status = %d, want %d; body: %s", rec.Code, status, rec.Body)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode response %s: %v", rec.Body, err)
+	}
+	return v
+}
+
+// checkError fails the test unless the response is an ErrorResponse with the
+// wanted status and code.
+func checkError(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+
+	resp := decode[dtos.ErrorResponseDTO](t, rec, status)
+	if resp.Error.Code != code {
+		t.Errorf("error code = %q, want %q (message %q)", resp.Error.Code, code, resp.Error.Message)
+	}
+	if resp.Error.Message == "" {
+		t.Error("error message is empty")
+	}
+}
+
+func members(ids ...string) []dtos.TeamMemberDTO {
+	list := make([]dtos.TeamMemberDTO, len(ids))
+	for i, id := range ids {
+		list[i] = dtos.TeamMemberDTO{UserID: id, Username: "user-" + id, IsActive: true}
+	}
+	return list
+}
+
+func addTeam(t *testing.T, router http.Handler, name string, userIDs ...string) {
+	t.Helper()
+
+	rec := do(t, router, http.MethodPost, "/team/add", dtos.TeamDTO{TeamName: name, Members: members(userIDs...)})
+	decode[dtos.TeamAddResponseDTO](t, rec, http.StatusCreated)
+}
+
+func createPR(t *testing.T, router http.Handler, prID, authorID string) dtos.PullRequestDTO {
+	t.Helper()
+
+	rec := do(t, router, http.MethodPost, "/pullRequest/create", dtos.PullRequestCreateRequestDTO{
+		PullRequestID:   prID,
+		PullRequestName: "Change " + prID,
+		AuthorID:        authorID,
+	})
+	return decode[dtos.PullRequestResponseDTO](t, rec, http.StatusCreated).Pr
+}
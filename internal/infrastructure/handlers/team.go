@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"pullrequest-manager/internal/infrastructure/dtos"
+)
+
+func (h *Handler) AddTeam(w http.ResponseWriter, r *http.Request) {
+	var req dtos.TeamDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.TeamName == "" {
+		writeBadRequest(w, errors.New("team_name is required"))
+		return
+	}
+	seen := make(map[string]bool, len(req.Members))
+	for _, member := range req.Members {
+		if member.UserID == "" {
+			writeBadRequest(w, errors.New("members[].user_id is required"))
+			return
+		}
+		if seen[member.UserID] {
+			writeBadRequest(w, fmt.Errorf("members[].user_id %q is listed twice", member.UserID))
+			return
+		}
+		seen[member.UserID] = true
+	}
+
+	if err := h.service.CreateTeam(r.Context(), req.TeamName, req.Members); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	team, err := h.service.GetTeam(r.Context(), req.TeamName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setETag(w, team.Version)
+	writeJSON(w, http.StatusCreated, dtos.TeamAddResponseDTO{Team: *team})
+}
+
+func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeBadRequest(w, errors.New("team_name query parameter is required"))
+		return
+	}
+
+	team, err := h.service.GetTeam(r.Context(), teamName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	setETag(w, team.Version)
+	writeJSON(w, http.StatusOK, team)
+}
+
+func (h *Handler) GetTeamSettings(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeBadRequest(w, errors.New("team_name query parameter is required"))
+		return
+	}
+
+	settings, err := h.service.GetTeamSettings(r.Context(), teamName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+func (h *Handler) UpdateTeamSettings(w http.ResponseWriter, r *http.Request) {
+	var req dtos.TeamSettingsDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.TeamName == "" {
+		writeBadRequest(w, errors.New("team_name is required"))
+		return
+	}
+
+	settings, err := h.service.UpdateTeamSettings(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
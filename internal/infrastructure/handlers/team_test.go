@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"net/http"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/handlers"
+	"testing"
+)
+
+func TestAddTeam(t *testing.T) {
+	router := newTestRouter(t)
+
+	rec := do(t, router, http.MethodPost, "/team/add", dtos.TeamDTO{TeamName: "backend", Members: members("u1", "u2")})
+	resp := decode[dtos.TeamAddResponseDTO](t, rec, http.StatusCreated)
+	if resp.Team.TeamName != "backend" || len(resp.Team.Members) != 2 {
+		t.Errorf("team = %+v, want backend with two members", resp.Team)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("ETag = %s, want \"1\"", etag)
+	}
+
+	// Replacing the roster needs the current version.
+	rec = do(t, router, http.MethodPost, "/team/add", dtos.TeamDTO{TeamName: "backend", Members: members("u1", "u2", "u3")}, "If-Match", `"1"`)
+	resp = decode[dtos.TeamAddResponseDTO](t, rec, http.StatusCreated)
+	if len(resp.Team.Members) != 3 || rec.Header().Get("ETag") != `"2"` {
+		t.Errorf("replaced team = %+v with ETag %s, want three members and \"2\"", resp.Team, rec.Header().Get("ETag"))
+	}
+
+	tests := []struct {
+		name   string
+		body   any
+		header []string
+		status int
+		code   string
+	}{
+		{"malformed JSON", `{"team_name":`, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"unknown field", `{"team_name":"x","members":[],"extra":1}`, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"missing name", dtos.TeamDTO{Members: members("u9")}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"missing user ID", dtos.TeamDTO{TeamName: "x", Members: members("")}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"duplicate member", dtos.TeamDTO{TeamName: "x", Members: members("u9", "u9")}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"team exists", dtos.TeamDTO{TeamName: "backend", Members: members("u1")}, nil, http.StatusBadRequest, handlers.CodeTeamExists},
+		{"stale If-Match", dtos.TeamDTO{TeamName: "backend", Members: members("u1")}, []string{"If-Match", `"1"`}, http.StatusPreconditionFailed, handlers.CodeVersionMismatch},
+		{
+			"username taken",
+			dtos.TeamDTO{TeamName: "frontend", Members: []dtos.TeamMemberDTO{{UserID: "u9", Username: "user-u1", IsActive: true}}},
+			nil, http.StatusConflict, handlers.CodeAlreadyExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, do(t, router, http.MethodPost, "/team/add", tt.body, tt.header...), tt.status, tt.code)
+		})
+	}
+}
+
+func TestGetTeam(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2")
+
+	rec := do(t, router, http.MethodGet, "/team/get?team_name=backend", nil)
+	team := decode[dtos.TeamDTO](t, rec, http.StatusOK)
+	if team.TeamName != "backend" || len(team.Members) != 2 || team.Members[0].UserID != "u1" {
+		t.Errorf("team = %+v, want backend with u1 and u2", team)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("ETag = %s, want \"1\"", etag)
+	}
+
+	checkError(t, do(t, router, http.MethodGet, "/team/get", nil), http.StatusBadRequest, handlers.CodeBadRequest)
+	checkError(t, do(t, router, http.MethodGet, "/team/get?team_name=mobile", nil), http.StatusNotFound, handlers.CodeNotFound)
+}
+
+func TestTeamSettings(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2")
+
+	update := dtos.TeamSettingsDTO{TeamName: "backend", ReviewerCount: 1, MinReviewers: 1, RequiredApprovals: 1}
+	got := decode[dtos.TeamSettingsDTO](t, do(t, router, http.MethodPost, "/team/settings", update), http.StatusOK)
+	if got.ReviewerCount != 1 || got.RequiredApprovals != 1 {
+		t.Errorf("updated settings = %+v", got)
+	}
+	got = decode[dtos.TeamSettingsDTO](t, do(t, router, http.MethodGet, "/team/settings?team_name=backend", nil), http.StatusOK)
+	if got.ReviewerCount != 1 || got.MinReviewers != 1 || got.RequiredApprovals != 1 {
+		t.Errorf("stored settings = %+v, want the update", got)
+	}
+
+	invalid := update
+	invalid.RequiredApprovals = 2
+	checkError(t, do(t, router, http.MethodPost, "/team/settings", invalid), http.StatusBadRequest, handlers.CodeBadRequest)
+	unknown := update
+	unknown.TeamName = "mobile"
+	checkError(t, do(t, router, http.MethodPost, "/team/settings", unknown), http.StatusNotFound, handlers.CodeNotFound)
+	checkError(t, do(t, router, http.MethodGet, "/team/settings?team_name=mobile", nil), http.StatusNotFound, handlers.CodeNotFound)
+	checkError(t, do(t, router, http.MethodGet, "/team/settings", nil), http.StatusBadRequest, handlers.CodeBadRequest)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pullrequest-manager/internal/infrastructure/dtos"
+)
+
+func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
+	var req dtos.UserSetActiveRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.UserID == "" {
+		writeBadRequest(w, errors.New("user_id is required"))
+		return
+	}
+	if req.IsActive && req.ReassignOpenReviews {
+		writeBadRequest(w, errors.New("reassign_open_reviews requires is_active to be false"))
+		return
+	}
+	// Users are not versioned, so there is nothing an If-Match could match.
+	if r.Header.Get(ifMatchHeader) != "" {
+		writeBadRequest(w, errors.New("If-Match is not supported on this endpoint"))
+		return
+	}
+
+	resp, err := h.service.SetUserActive(r.Context(), req.UserID, req.IsActive, req.ReassignOpenReviews)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeBadRequest(w, errors.New("user_id query parameter is required"))
+		return
+	}
+
+	reviews, err := h.service.GetUserReviews(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if reviews.PullRequests == nil {
+		reviews.PullRequests = []dtos.PullRequestShortDTO{}
+	}
+
+	writeJSON(w, http.StatusOK, reviews)
+}
@@ -0,0 +1,68 @@
+package handlers_test
+
+import (
+	"net/http"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/handlers"
+	"testing"
+)
+
+func TestSetUserActive(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3", "u4")
+	pr := createPR(t, router, "pr-1", "u1")
+	leaving := pr.AssignedReviewers[0]
+
+	rec := do(t, router, http.MethodPost, "/users/setIsActive", dtos.UserSetActiveRequestDTO{UserID: leaving, ReassignOpenReviews: true})
+	resp := decode[dtos.UserSetActiveResponseDTO](t, rec, http.StatusOK)
+	if resp.User.UserID != leaving || resp.User.IsActive || resp.User.TeamName != "backend" {
+		t.Errorf("user = %+v, want %s deactivated in backend", resp.User, leaving)
+	}
+	if resp.Reassignment == nil || len(resp.Reassignment.Reassigned) != 1 || resp.Reassignment.Reassigned[0].PullRequestID != "pr-1" {
+		t.Fatalf("reassignment = %+v, want pr-1 reassigned", resp.Reassignment)
+	}
+
+	rec = do(t, router, http.MethodPost, "/users/setIsActive", dtos.UserSetActiveRequestDTO{UserID: leaving, IsActive: true})
+	if resp := decode[dtos.UserSetActiveResponseDTO](t, rec, http.StatusOK); !resp.User.IsActive || resp.Reassignment != nil {
+		t.Errorf("reactivated = %+v, want an active user without a report", resp)
+	}
+
+	tests := []struct {
+		name   string
+		body   any
+		header []string
+		status int
+		code   string
+	}{
+		{"malformed JSON", `{"user_id":`, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"missing user ID", dtos.UserSetActiveRequestDTO{IsActive: true}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"reassign while active", dtos.UserSetActiveRequestDTO{UserID: "u2", IsActive: true, ReassignOpenReviews: true}, nil, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"If-Match", dtos.UserSetActiveRequestDTO{UserID: "u2"}, []string{"If-Match", `"1"`}, http.StatusBadRequest, handlers.CodeBadRequest},
+		{"unknown user", dtos.UserSetActiveRequestDTO{UserID: "nobody"}, nil, http.StatusNotFound, handlers.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkError(t, do(t, router, http.MethodPost, "/users/setIsActive", tt.body, tt.header...), tt.status, tt.code)
+		})
+	}
+}
+
+func TestGetUserReviews(t *testing.T) {
+	router := newTestRouter(t)
+	addTeam(t, router, "backend", "u1", "u2", "u3")
+	pr := createPR(t, router, "pr-1", "u1")
+
+	rec := do(t, router, http.MethodGet, "/users/getReview?user_id="+pr.AssignedReviewers[0], nil)
+	reviews := decode[dtos.UserGetReviewResponseDTO](t, rec, http.StatusOK)
+	if len(reviews.PullRequests) != 1 || reviews.PullRequests[0].PullRequestID != "pr-1" || reviews.PullRequests[0].Status != "OPEN" {
+		t.Errorf("reviews = %+v, want open pr-1", reviews)
+	}
+
+	// Users nobody has heard of simply have nothing to review.
+	rec = do(t, router, http.MethodGet, "/users/getReview?user_id=nobody", nil)
+	if reviews := decode[dtos.UserGetReviewResponseDTO](t, rec, http.StatusOK); reviews.PullRequests == nil || len(reviews.PullRequests) != 0 {
+		t.Errorf("reviews = %+v, want an empty list", reviews)
+	}
+
+	checkError(t, do(t, router, http.MethodGet, "/users/getReview", nil), http.StatusBadRequest, handlers.CodeBadRequest)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/infrastructure/dtos"
+)
+
+type WebhookHandler struct {
+	service *services.DefaultWebhookService
+}
+
+func NewWebhookHandler(service *services.DefaultWebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+func (h *WebhookHandler) AddWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dtos.WebhookCreateRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.URL == "" {
+		writeBadRequest(w, errors.New("url is required"))
+		return
+	}
+
+	webhook, err := h.service.AddWebhook(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, dtos.WebhookResponseDTO{Webhook: *webhook})
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.WebhookListResponseDTO{Webhooks: webhooks})
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dtos.WebhookDeleteRequestDTO
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if req.ID == "" {
+		writeBadRequest(w, errors.New("id is required"))
+		return
+	}
+
+	if err := h.service.DeleteWebhook(r.Context(), req.ID); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package handlers_test
+
+import (
+	"net/http"
+	"pullrequest-manager/internal/infrastructure/dtos"
+	"pullrequest-manager/internal/infrastructure/handlers"
+	"testing"
+)
+
+func TestAddWebhook(t *testing.T) {
+	router := newTestRouter(t)
+
+	rec := do(t, router, http.MethodPost, "/webhooks/add", dtos.WebhookCreateRequestDTO{URL: "https://hooks.example.com/prm"})
+	resp := decode[dtos.WebhookResponseDTO](t, rec, http.StatusCreated)
+	if resp.Webhook.URL != "https://hooks.example.com/prm" || resp.Webhook.Secret == "" {
+		t.Errorf("webhook = %+v, want the URL and a generated secret", resp.Webhook)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"relative URL", "/prm"},
+		{"unsupported scheme", "ftp://hooks.example.com/prm"},
+		{"loopback", "http://127.0.0.1:8080/prm"},
+		{"localhost", "http://localhost/prm"},
+		{"private", "http://10.0.0.5/prm"},
+		{"link-local", "http://169.254.169.254/latest/meta-data"},
+		{"IPv6 loopback", "http://[::1]/prm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, router, http.MethodPost, "/webhooks/add", dtos.WebhookCreateRequestDTO{URL: tt.url})
+			checkError(t, rec, http.StatusBadRequest, handlers.CodeBadRequest)
+		})
+	}
+}
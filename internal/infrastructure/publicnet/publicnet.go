@@ -0,0 +1,55 @@
+// Package publicnet provides an HTTP client that only connects to public
+// addresses, for requests to user-supplied URLs such as calendar feeds and
+// webhook endpoints, so that they cannot reach services on the internal
+// network.
+package publicnet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenDestination is returned for connections to loopback, private,
+// link-local or otherwise non-public addresses.
+var ErrForbiddenDestination = errors.New("destination address is not public")
+
+// NewClient checks every address it connects to, after DNS resolution, so
+// redirects and DNS rebinding are covered too. Proxies from the environment
+// are not used because they would hide the destination.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrForbiddenDestination, address)
+			}
+			if !IsPublic(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenDestination, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, which
+// netip does not count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!sharedAddressSpace.Contains(addr)
+}
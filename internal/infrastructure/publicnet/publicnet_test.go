@@ -0,0 +1,58 @@
+package publicnet_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"pullrequest-manager/internal/infrastructure/publicnet"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientRejectsNonPublicDestinations(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer srv.Close()
+
+	client := publicnet.NewClient(time.Second)
+	for _, rawURL := range []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)} {
+		resp, err := client.Get(rawURL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if !errors.Is(err, publicnet.ErrForbiddenDestination) {
+			t.Errorf("Get(%s) = %v, want ErrForbiddenDestination", rawURL, err)
+		}
+	}
+	if hits != 0 {
+		t.Errorf("server got %d requests, want none", hits)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.215.14":        true,
+		"2606:2800:21f:cb07::": true,
+		"127.0.0.1":            false,
+		"::1":                  false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"169.254.169.254":      false,
+		"100.64.0.1":           false,
+		"0.0.0.0":              false,
+		"::":                   false,
+		"224.0.0.1":            false,
+		"fd00::1":              false,
+		"fe80::1":              false,
+		"::ffff:127.0.0.1":     false,
+		"::ffff:10.0.0.1":      false,
+	}
+	for addr, want := range tests {
+		if got := publicnet.IsPublic(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("IsPublic(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import "errors"
+
+var (
+	ErrPullRequestNotFound  = errors.New("pull request not found")
+	ErrStatusNotFound       = errors.New("status not found")
+	ErrTeamNotFound         = errors.New("team not found")
+	ErrTeamSettingsNotFound = errors.New("team settings not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrUserIdentityNotFound = errors.New("user identity not found")
+	ErrWebhookNotFound      = errors.New("webhook subscription not found")
+
+	ErrUnavailabilityNotFound = errors.New("unavailability period not found")
+	ErrCalendarSourceNotFound = errors.New("calendar source not found")
+
+	// ErrConcurrentModification is returned by Update when the stored version
+	// no longer matches the entity's, i.e. someone else updated it first.
+	ErrConcurrentModification = errors.New("entity was modified concurrently")
+
+	// ErrDuplicateKey is returned by Create and Update when a unique key such
+	// as a team name, username or external id is already taken.
+	ErrDuplicateKey = errors.New("duplicate key")
+)
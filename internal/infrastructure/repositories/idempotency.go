@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"time"
+)
+
+type IdempotencyKey interface {
+	// Reserve stores rec as in flight unless a live record for the same scope
+	// and key exists, in which case that record is returned instead. Expired
+	// records, and in-flight ones created before staleBefore, are replaced.
+	Reserve(ctx context.Context, rec *models.IdempotencyRecord, staleBefore time.Time) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, rec *models.IdempotencyRecord) error
+	Release(ctx context.Context, scope string, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
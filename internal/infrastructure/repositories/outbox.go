@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"time"
+)
+
+type Outbox interface {
+	// Process claims up to limit due, unpublished events with fewer than
+	// maxAttempts attempts, oldest first, and passes them to handle one by one
+	// outside any transaction. Claimed events are leased for lease, so other
+	// relays skip them while they are handled. Handled events are marked
+	// published; a failure is recorded on its event, which then waits
+	// retryDelay(attempts) before its next attempt. Events of one aggregate go
+	// out in order: an event is not handled while an earlier event of its
+	// aggregate is waiting or has just failed. It returns the number of events
+	// published.
+	Process(
+		ctx context.Context,
+		limit int,
+		maxAttempts int,
+		lease time.Duration,
+		retryDelay func(attempts int) time.Duration,
+		handle func(ctx context.Context, event *models.OutboxEvent) error,
+	) (int, error)
+}
@@ -3,11 +3,38 @@ package repositories
 import (
 	"context"
 	"pullrequest-manager/internal/domain/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// PullRequestFilter narrows list queries. Zero fields do not filter; time
+// ranges are half-open [From, To). Results are ordered by created_at and ID,
+// newest first, and After continues a previous page.
+type PullRequestFilter struct {
+	Statuses    []models.StatusName
+	AuthorIDs   []uuid.UUID
+	ReviewerID  *uuid.UUID
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	After       *PullRequestCursor
+	Limit       int
+}
+
+// PullRequestCursor is the keyset position of the last row of a page.
+type PullRequestCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
 type PullRequest interface {
 	Repository[models.PullRequest, uuid.UUID]
+	FindByExternalID(ctx context.Context, externalID string) (*models.PullRequest, error)
 	FindByAuthor(ctx context.Context, userID uuid.UUID) ([]*models.PullRequest, error)
+	FindByReviewer(ctx context.Context, reviewerID uuid.UUID, filter PullRequestFilter) ([]*models.PullRequest, error)
+	List(ctx context.Context, filter PullRequestFilter) ([]*models.PullRequest, error)
+	CountOpenReviews(ctx context.Context, reviewerIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	FindAssignmentEvents(ctx context.Context, prID uuid.UUID) ([]*models.AssignmentEvent, error)
 }
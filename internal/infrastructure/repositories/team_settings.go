@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type TeamSettings interface {
+	FindByTeamID(ctx context.Context, teamID uuid.UUID) (*models.TeamSettings, error)
+	Upsert(ctx context.Context, settings *models.TeamSettings) error
+}
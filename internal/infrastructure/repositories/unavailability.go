@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Unavailability interface {
+	Create(ctx context.Context, period *models.UserUnavailability) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserUnavailability, error)
+	DeleteByID(ctx context.Context, id uuid.UUID) error
+	DeleteBySourceID(ctx context.Context, sourceID uuid.UUID) error
+	// FindUnavailableUserIDs reports which of userIDs have a period covering at.
+	FindUnavailableUserIDs(ctx context.Context, userIDs []uuid.UUID, at time.Time) (map[uuid.UUID]bool, error)
+}
+
+type CalendarSource interface {
+	// Upsert creates the source, or loads the existing one with the same user
+	// and URL into source.
+	Upsert(ctx context.Context, source *models.CalendarSource) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.CalendarSource, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.CalendarSource, error)
+	// FindWithURL returns the sources the sync job refreshes.
+	FindWithURL(ctx context.Context) ([]*models.CalendarSource, error)
+	// Update stores the outcome of the last sync.
+	Update(ctx context.Context, source *models.CalendarSource) error
+	DeleteByID(ctx context.Context, id uuid.UUID) error
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"pullrequest-manager/internal/domain/models"
 
 	"github.com/google/uuid"
@@ -8,4 +9,5 @@ import (
 
 type User interface {
 	Repository[models.User, uuid.UUID]
+	FindByExternalID(ctx context.Context, externalID string) (*models.User, error)
 }
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type UserIdentity interface {
+	FindByLogin(ctx context.Context, provider string, login string) (*models.UserIdentity, error)
+	FindByUserID(ctx context.Context, provider string, userID uuid.UUID) (*models.UserIdentity, error)
+	Upsert(ctx context.Context, identity *models.UserIdentity) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"pullrequest-manager/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscription interface {
+	Create(ctx context.Context, sub *models.WebhookSubscription) error
+	FindAll(ctx context.Context) ([]*models.WebhookSubscription, error)
+	FindByEventType(ctx context.Context, eventType string) ([]*models.WebhookSubscription, error)
+	DeleteByID(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookDeadLetter interface {
+	Create(ctx context.Context, deadLetter *models.WebhookDeadLetter) error
+}
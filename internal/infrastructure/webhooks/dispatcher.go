@@ -0,0 +1,197 @@
+// Package webhooks delivers service events to subscribed HTTP endpoints as
+// HMAC-SHA256 signed JSON. Retries are left to the outbox relay; deliveries
+// that still fail on its last attempt are recorded in a dead-letter store.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/publicnet"
+	"pullrequest-manager/internal/infrastructure/repositories"
+	"sync"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature-256"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	signaturePrefix = "sha256="
+	userAgent       = "pullrequest-manager-webhooks"
+
+	deadLetterTimeout = 5 * time.Second
+)
+
+var ErrDeliveryInterrupted = errors.New("webhook delivery interrupted")
+
+// Options configures delivery. Workers bounds how many subscriptions of one
+// event are delivered to in parallel. The default Client only connects to
+// public addresses, since subscription URLs are user supplied.
+type Options struct {
+	Client  *http.Client
+	Workers int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Client:  publicnet.NewClient(10 * time.Second),
+		Workers: 4,
+	}
+}
+
+type delivery struct {
+	sub     *models.WebhookSubscription
+	event   services.Event
+	payload []byte
+}
+
+// Dispatcher implements services.EventPublisher. Publish returns once every
+// subscription has either received the event or had it dead-lettered.
+type Dispatcher struct {
+	subscriptions repositories.WebhookSubscription
+	deadLetters   repositories.WebhookDeadLetter
+	opts          Options
+}
+
+func NewDispatcher(
+	subscriptions repositories.WebhookSubscription,
+	deadLetters repositories.WebhookDeadLetter,
+	opts Options,
+) *Dispatcher {
+	defaults := DefaultOptions()
+	if opts.Client == nil {
+		opts.Client = defaults.Client
+	}
+	if opts.Workers < 1 {
+		opts.Workers = defaults.Workers
+	}
+
+	return &Dispatcher{
+		subscriptions: subscriptions,
+		deadLetters:   deadLetters,
+		opts:          opts,
+	}
+}
+
+// Publish sends the event once to every subscription. A failed delivery
+// fails Publish so that the outbox retries the event with its backoff; on
+// the outbox's last attempt the delivery is dead-lettered instead.
+// Subscriptions that already received the event get it again on every retry,
+// with the same delivery ID.
+func (d *Dispatcher) Publish(ctx context.Context, event services.Event) error {
+	subs, err := d.subscriptions.FindByEventType(ctx, event.Type)
+	if err != nil {
+		return fmt.Errorf("find subscriptions for %s: %w", event.Type, err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode %s event: %w", event.Type, err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, d.opts.Workers)
+		errs = make([]error, len(subs))
+	)
+	for i, sub := range subs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = d.deliver(ctx, delivery{sub: sub, event: event, payload: payload})
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, dl delivery) error {
+	err := d.send(ctx, dl)
+	switch {
+	case err == nil:
+		return nil
+	case ctx.Err() != nil:
+		return fmt.Errorf("webhook %s: %w: %w", dl.sub.ID, ErrDeliveryInterrupted, err)
+	case dl.event.LastAttempt:
+		return d.deadLetter(dl, dl.event.Attempt, err)
+	default:
+		return fmt.Errorf("webhook %s: %w", dl.sub.ID, err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, dl delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dl.sub.URL, bytes.NewReader(dl.payload))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, dl.event.Type)
+	req.Header.Set(DeliveryHeader, dl.event.ID.String())
+	req.Header.Set(SignatureHeader, Sign(dl.sub.Secret, dl.payload))
+
+	resp, err := d.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *Dispatcher) deadLetter(dl delivery, attempts int, cause error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deadLetterTimeout)
+	defer cancel()
+
+	subID := dl.sub.ID
+	record := &models.WebhookDeadLetter{
+		SubscriptionID: &subID,
+		EventID:        dl.event.ID,
+		EventType:      dl.event.Type,
+		URL:            dl.sub.URL,
+		Payload:        dl.payload,
+		Attempts:       attempts,
+		LastError:      cause.Error(),
+	}
+	if err := d.deadLetters.Create(ctx, record); err != nil {
+		return fmt.Errorf("webhook %s: dead-letter event %s: %w (delivery error: %v)", dl.sub.ID, dl.event.ID, err, cause)
+	}
+	log.Printf("webhook %s: event %s dead-lettered after %d attempt(s): %v", dl.sub.ID, dl.event.ID, attempts, cause)
+	return nil
+}
+
+// Sign returns the signature header value for body: "sha256=" followed by
+// the hex HMAC-SHA256 of body keyed with the subscription secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is a valid Sign result for body. Receivers
+// can use it to authenticate deliveries.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
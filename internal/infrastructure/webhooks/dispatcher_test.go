@@ -0,0 +1,271 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"pullrequest-manager/internal/application/services"
+	"pullrequest-manager/internal/domain/models"
+	"pullrequest-manager/internal/infrastructure/database/memory"
+	"pullrequest-manager/internal/infrastructure/publicnet"
+	"pullrequest-manager/internal/infrastructure/webhooks"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const secret = "s3cret"
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver answers with the statuses in order, repeating the last one, and
+// records every request.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []received
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, received{header: r.Header.Clone(), body: body})
+	status := http.StatusNoContent
+	if len(rc.statuses) > 0 {
+		status = rc.statuses[0]
+		if len(rc.statuses) > 1 {
+			rc.statuses = rc.statuses[1:]
+		}
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) received() []received {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]received(nil), rc.requests...)
+}
+
+// deadLetters records dead letters, or fails with err.
+type deadLetters struct {
+	mu   sync.Mutex
+	list []models.WebhookDeadLetter
+	err  error
+}
+
+func (d *deadLetters) Create(ctx context.Context, dl *models.WebhookDeadLetter) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.list = append(d.list, *dl)
+	return nil
+}
+
+func newDispatcher(t *testing.T, rc *receiver, dls *deadLetters, opts webhooks.Options, eventTypes ...string) *webhooks.Dispatcher {
+	t.Helper()
+
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+	// The test server listens on loopback, which the default client refuses.
+	if opts.Client == nil {
+		opts.Client = srv.Client()
+	}
+
+	subs := memory.NewWebhookSubscriptionRepository()
+	if err := subs.Create(context.Background(), &models.WebhookSubscription{URL: srv.URL, Secret: secret, EventTypes: eventTypes}); err != nil {
+		t.Fatal(err)
+	}
+	return webhooks.NewDispatcher(subs, dls, opts)
+}
+
+func testEvent(eventType string) services.Event {
+	return services.Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC().Truncate(time.Second),
+		Data:       json.RawMessage(`{"pull_request_id":"pr-1"}`),
+	}
+}
+
+func TestPublishSignsDelivery(t *testing.T) {
+	rc := &receiver{}
+	d := newDispatcher(t, rc, &deadLetters{}, webhooks.Options{})
+	event := testEvent(services.EventPullRequestMerged)
+
+	if err := d.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	reqs := rc.received()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	req := reqs[0]
+	if !webhooks.Verify(secret, req.body, req.header.Get(webhooks.SignatureHeader)) {
+		t.Errorf("signature %q does not verify", req.header.Get(webhooks.SignatureHeader))
+	}
+	if got := req.header.Get(webhooks.EventHeader); got != event.Type {
+		t.Errorf("%s = %q, want %q", webhooks.EventHeader, got, event.Type)
+	}
+	if got := req.header.Get(webhooks.DeliveryHeader); got != event.ID.String() {
+		t.Errorf("%s = %q, want %q", webhooks.DeliveryHeader, got, event.ID)
+	}
+
+	var got services.Event
+	if err := json.Unmarshal(req.body, &got); err != nil || got.ID != event.ID || string(got.Data) != string(event.Data) {
+		t.Errorf("body = %s (%v), want the event", req.body, err)
+	}
+}
+
+func TestPublishRefusesNonPublicReceivers(t *testing.T) {
+	rc := &receiver{}
+	srv := httptest.NewServer(rc)
+	defer srv.Close()
+
+	subs := memory.NewWebhookSubscriptionRepository()
+	if err := subs.Create(context.Background(), &models.WebhookSubscription{URL: srv.URL, Secret: secret}); err != nil {
+		t.Fatal(err)
+	}
+	d := webhooks.NewDispatcher(subs, &deadLetters{}, webhooks.DefaultOptions())
+
+	err := d.Publish(context.Background(), testEvent(services.EventPullRequestCreated))
+	if !errors.Is(err, publicnet.ErrForbiddenDestination) {
+		t.Errorf("Publish = %v, want ErrForbiddenDestination", err)
+	}
+	if n := len(rc.received()); n != 0 {
+		t.Errorf("got %d requests, want none", n)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"pullRequest.merged"}`)
+	signature := webhooks.Sign(secret, body)
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Errorf("Sign = %q, want a sha256= prefix", signature)
+	}
+	if !webhooks.Verify(secret, body, signature) {
+		t.Error("Verify rejected a valid signature")
+	}
+	if webhooks.Verify("other", body, signature) {
+		t.Error("Verify accepted a signature made with another secret")
+	}
+	if webhooks.Verify(secret, []byte(`{"type":"pullRequest.created"}`), signature) {
+		t.Error("Verify accepted a signature for another body")
+	}
+}
+
+func TestPublishSkipsUnsubscribedEvents(t *testing.T) {
+	rc := &receiver{}
+	d := newDispatcher(t, rc, &deadLetters{}, webhooks.Options{}, services.EventPullRequestCreated)
+
+	if err := d.Publish(context.Background(), testEvent(services.EventPullRequestMerged)); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if n := len(rc.received()); n != 0 {
+		t.Errorf("got %d requests, want none", n)
+	}
+}
+
+func TestPublishFailsForOutboxRetry(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusOK}}
+	dls := &deadLetters{}
+	d := newDispatcher(t, rc, dls, webhooks.Options{})
+	event := testEvent(services.EventPullRequestCreated)
+	event.Attempt = 1
+
+	// A failed delivery is sent once and left to the outbox to retry.
+	if err := d.Publish(context.Background(), event); err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("Publish = %v, want the 500 response", err)
+	}
+	if n := len(rc.received()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+
+	event.Attempt = 2
+	if err := d.Publish(context.Background(), event); err != nil {
+		t.Fatalf("retried Publish: %v", err)
+	}
+	reqs := rc.received()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	if reqs[0].header.Get(webhooks.DeliveryHeader) != reqs[1].header.Get(webhooks.DeliveryHeader) {
+		t.Error("retries use a different delivery ID")
+	}
+	if len(dls.list) != 0 {
+		t.Errorf("dead letters = %+v, want none", dls.list)
+	}
+}
+
+func TestPublishDeadLettersOnLastAttempt(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+	dls := &deadLetters{}
+	d := newDispatcher(t, rc, dls, webhooks.Options{})
+	event := testEvent(services.EventPullRequestCreated)
+	event.Attempt, event.LastAttempt = 3, true
+
+	// A dead-lettered event counts as handled so the outbox can move on.
+	if err := d.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if n := len(rc.received()); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+	if len(dls.list) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(dls.list))
+	}
+	dl := dls.list[0]
+	if dl.EventID != event.ID || dl.EventType != event.Type || dl.Attempts != 3 || !strings.Contains(dl.LastError, "503") {
+		t.Errorf("dead letter = %+v", dl)
+	}
+	if !webhooks.Verify(secret, dl.Payload, rc.received()[0].header.Get(webhooks.SignatureHeader)) {
+		t.Error("dead letter payload differs from the delivered body")
+	}
+}
+
+func TestPublishFailsWhenDeadLetterIsLost(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+	dls := &deadLetters{err: errors.New("db down")}
+	d := newDispatcher(t, rc, dls, webhooks.Options{})
+	event := testEvent(services.EventPullRequestCreated)
+	event.Attempt, event.LastAttempt = 2, true
+
+	if err := d.Publish(context.Background(), event); err == nil {
+		t.Fatal("Publish succeeded although the dead letter was not stored")
+	}
+}
+
+func TestPublishInterruptedByContext(t *testing.T) {
+	rc := &receiver{}
+	dls := &deadLetters{}
+	d := newDispatcher(t, rc, dls, webhooks.Options{})
+	event := testEvent(services.EventPullRequestCreated)
+	event.Attempt, event.LastAttempt = 1, true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Even the last attempt is not dead-lettered when the relay stops.
+	err := d.Publish(ctx, event)
+	if !errors.Is(err, webhooks.ErrDeliveryInterrupted) {
+		t.Fatalf("Publish = %v, want ErrDeliveryInterrupted", err)
+	}
+	if len(dls.list) != 0 {
+		t.Errorf("dead letters = %+v, want none", dls.list)
+	}
+}